@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/impact"
+	"github.com/spf13/cobra"
+)
+
+var (
+	AffectedCommand = &cobra.Command{
+		Use:   "affected [FILE...]",
+		Short: "List testcases affected by changed files",
+		Long: `List testcases affected by changed files.
+
+The affected command lists the testcases of the test suite, which depend
+directly or indirectly on definitions modified by a change. The change is
+either given as list of modified files or as git revision range with --diff:
+
+	ntt affected src/a.ttcn3 src/b.ttcn3
+	ntt affected --diff origin/master...HEAD
+	ntt affected --diff HEAD         # uncommitted changes
+
+With --diff only the modified lines are considered, which allows to select
+less tests. The working tree is expected to match the end of the range.
+Changes of imports and changes outside of any definition affect all
+definitions of the module. Deleted files are ignored.
+
+Affected testcases are printed one per line. With --as-basket the testcases
+are printed as filter of a test basket, which can be used with ntt list:
+
+	export NTT_LIST_BASKETS_affected="$(ntt affected --as-basket --diff HEAD~1)"
+	NTT_LIST_BASKETS=affected ntt list
+`,
+		Annotations: map[string]string{projectArgs: projectArgsNone},
+		RunE:        affected,
+	}
+
+	affectedDiff   string
+	affectedBasket bool
+)
+
+func init() {
+	AffectedCommand.Flags().StringVar(&affectedDiff, "diff", "", "use changes of git revision `RANGE`")
+	AffectedCommand.Flags().BoolVar(&affectedBasket, "as-basket", false, "print affected testcases as basket filter")
+}
+
+func affected(cmd *cobra.Command, args []string) error {
+	files, err := project.Files(Project)
+	if err != nil {
+		return err
+	}
+
+	changes, err := changedFiles(args)
+	if err != nil {
+		return err
+	}
+
+	// Changes of non TTCN-3 files or files outside of the suite are
+	// irrelevant.
+	suite := make(map[string]bool)
+	for _, f := range files {
+		suite[absPath(f)] = true
+	}
+	var relevant []impact.Change
+	for _, c := range changes {
+		if !suite[absPath(c.File)] {
+			log.Verbosef("ignoring %s: not part of test suite\n", c.File)
+			continue
+		}
+		relevant = append(relevant, c)
+	}
+
+	var db ttcn3.DB
+	db.Index(files...)
+	tests := impact.Affected(&db, relevant...)
+
+	switch {
+	case outputJSON:
+		b, err := json.MarshalIndent(tests, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	case affectedBasket:
+		fmt.Println(basketFilter(tests))
+	default:
+		for _, t := range tests {
+			fmt.Println(t)
+		}
+	}
+	return nil
+}
+
+// changedFiles returns the changes given by command line arguments or by
+// the --diff flag.
+func changedFiles(args []string) ([]impact.Change, error) {
+	var changes []impact.Change
+	for _, arg := range args {
+		changes = append(changes, impact.Change{File: arg})
+	}
+	if affectedDiff == "" {
+		return changes, nil
+	}
+
+	root, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	// Explicit prefixes make the output independent of settings like
+	// diff.noprefix or diff.mnemonicPrefix.
+	diff, err := gitOutput("diff", "-U0", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/", affectedDiff, "--")
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range impact.ParseDiff([]byte(diff)) {
+		c.File = filepath.Join(strings.TrimSpace(root), c.File)
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+func gitOutput(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+func absPath(path string) string {
+	path = fs.Path(path)
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// basketFilter returns a basket filter matching exactly the given tests.
+func basketFilter(tests []string) string {
+	return "--regex=" + exactRegex(tests)
+}
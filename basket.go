@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/nokia/ntt/internal/env"
+	"github.com/nokia/ntt/internal/results"
 	"github.com/nokia/ntt/project"
 	"github.com/spf13/pflag"
 )
@@ -113,6 +114,19 @@ type Basket struct {
 
 	// Baskets are sub-baskets to be ORed.
 	Baskets []Basket
+
+	// Terms are the terms of a basket expression (see ParseBasketExpr).
+	// Objects must match the expression in addition to the filters above.
+	Terms []BasketTerm
+}
+
+// A BasketTerm is a term of a basket expression.
+type BasketTerm struct {
+	// Exclude is true if objects matching the term are removed from the
+	// result.
+	Exclude bool
+
+	Basket Basket
 }
 
 // NewBasket creates a new basket and parses the given arguments.
@@ -154,22 +168,8 @@ func NewBasketWithFlags(name string, fs *pflag.FlagSet) (Basket, error) {
 // Load baskets from given environment variable from environment
 // or from configuration.
 func (b *Basket) LoadFromEnvOrConfig(c *project.Config, key string) error {
-	get := func(name string) string {
-		if s, ok := env.LookupEnv(name); ok {
-			return s
-		}
-
-		if c != nil {
-			if c.Variables[name] != "" {
-				return c.Variables[name]
-			}
-			if strings.HasPrefix(name, "NTT") {
-				return c.Variables[strings.Replace(name, "NTT", "K3", 1)]
-			}
-		}
-		return ""
-	}
-	s := get(key)
+	r := basketResolver{conf: c, key: key}
+	s := r.get(key)
 	if s == "" {
 		return nil
 	}
@@ -179,20 +179,212 @@ func (b *Basket) LoadFromEnvOrConfig(c *project.Config, key string) error {
 		if name == "" {
 			continue
 		}
-		args := strings.Fields(get(fmt.Sprintf("%s_%s", key, name)))
-		if len(args) == 0 {
-			args = []string{"-R", "@" + name}
+		sb, ok, err := r.resolve(name)
+		if err != nil {
+			return err
 		}
+		if !ok {
+			sb, err = NewBasket(name, "-R", "@"+name)
+			if err != nil {
+				return err
+			}
+		}
+		b.Baskets = append(b.Baskets, sb)
+	}
+	return nil
+}
+
+// loadBaskets loads the baskets selected with flag --basket. Without flag
+// --basket the baskets listed in NTT_LIST_BASKETS are loaded.
+func loadBaskets(b *Basket, c *project.Config, fs *pflag.FlagSet) error {
+	if names, _ := fs.GetStringSlice("basket"); len(names) > 0 {
+		return b.LoadNamed(c, "NTT_LIST_BASKETS", names...)
+	}
+	return b.LoadFromEnvOrConfig(c, "NTT_LIST_BASKETS")
+}
 
-		sb, err := NewBasket(name, args...)
+// LoadNamed adds the named baskets as sub-baskets. Baskets are defined by
+// environment variables of the form <key>_<name>, by the baskets section of
+// the manifest or, for basket "failed", by the latest test results. Unlike
+// LoadFromEnvOrConfig, undefined baskets are an error.
+func (b *Basket) LoadNamed(c *project.Config, key string, names ...string) error {
+	r := basketResolver{conf: c, key: key}
+	for _, name := range names {
+		sb, ok, err := r.resolve(name)
 		if err != nil {
 			return err
 		}
+		if !ok {
+			return fmt.Errorf("basket %q: %w", name, project.ErrNotFound)
+		}
 		b.Baskets = append(b.Baskets, sb)
 	}
 	return nil
 }
 
+// ParseBasketExpr parses a basket expression. An expression is a list of
+// terms separated by " + " or " - ":
+//
+//	sanity + @priority: high - @slow
+//
+// Terms are evaluated from left to right: an object is added to the basket
+// if it matches a term following a "+" (or the first term) and removed if
+// it matches a term following a "-". A term is either
+//
+//   - a tag, optionally with a value regular expression (e.g. @slow or
+//     @priority: high),
+//   - a regular expression for the object name enclosed by slashes (e.g.
+//     /^sanity\./) or
+//   - the name of another basket.
+//
+// Basket names are resolved by the given function.
+func ParseBasketExpr(name string, expr string, resolve func(string) (Basket, error)) (Basket, error) {
+	b := Basket{Name: name}
+	// Padding detects operators at begin and end of the expression.
+	expr = " " + strings.TrimSpace(expr) + " "
+	terms := basketOp.Split(expr, -1)
+	ops := basketOp.FindAllStringSubmatch(expr, -1)
+	for i, term := range terms {
+		term = strings.TrimSpace(term)
+		t := BasketTerm{Exclude: i > 0 && ops[i-1][1] == "-"}
+		switch {
+		case term == "":
+			return b, fmt.Errorf("basket %q: missing term in %q", name, expr)
+		case strings.HasPrefix(term, "@"):
+			f := strings.SplitN(term, ":", 2)
+			tag := "^" + regexp.QuoteMeta(strings.TrimSpace(f[0])) + "$"
+			if len(f) > 1 {
+				tag += ":" + f[1]
+			}
+			t.Basket = Basket{Name: term, TagsRegex: []string{tag}}
+		case len(term) > 1 && strings.HasPrefix(term, "/") && strings.HasSuffix(term, "/"):
+			re := term[1 : len(term)-1]
+			if _, err := regexp.Compile(re); err != nil {
+				return b, fmt.Errorf("basket %q: %w", name, err)
+			}
+			t.Basket = Basket{Name: term, NameRegex: []string{re}}
+		default:
+			sb, err := resolve(term)
+			if err != nil {
+				return b, err
+			}
+			t.Basket = sb
+		}
+		b.Terms = append(b.Terms, t)
+	}
+	return b, nil
+}
+
+// basketOp matches the operators of basket expressions.
+var basketOp = regexp.MustCompile(`\s+([+-])\s+`)
+
+// basketResolver resolves basket names.
+type basketResolver struct {
+	conf *project.Config
+	key  string
+
+	// active are the baskets being resolved, for detecting cycles.
+	active []string
+}
+
+func (r *basketResolver) get(name string) string {
+	if s, ok := env.LookupEnv(name); ok {
+		return s
+	}
+
+	if r.conf != nil {
+		if r.conf.Variables[name] != "" {
+			return r.conf.Variables[name]
+		}
+		if strings.HasPrefix(name, "NTT") {
+			return r.conf.Variables[strings.Replace(name, "NTT", "K3", 1)]
+		}
+	}
+	return ""
+}
+
+// resolve returns the basket with the given name. The second return value is
+// false if the basket is not defined.
+func (r *basketResolver) resolve(name string) (Basket, bool, error) {
+	for i, active := range r.active {
+		if active == name {
+			return Basket{}, false, fmt.Errorf("basket %q: cycle %s", name, strings.Join(append(r.active[i:], name), " -> "))
+		}
+	}
+	r.active = append(r.active, name)
+	defer func() { r.active = r.active[:len(r.active)-1] }()
+
+	if args := strings.Fields(r.get(fmt.Sprintf("%s_%s", r.key, name))); len(args) > 0 {
+		b, err := NewBasket(name, args...)
+		return b, true, err
+	}
+
+	if r.conf != nil {
+		if expr, ok := r.conf.Baskets[name]; ok {
+			if strings.HasPrefix(strings.TrimSpace(expr), "-") {
+				b, err := NewBasket(name, strings.Fields(expr)...)
+				return b, true, err
+			}
+			b, err := ParseBasketExpr(name, expr, func(ref string) (Basket, error) {
+				b, ok, err := r.resolve(ref)
+				if err == nil && !ok {
+					err = fmt.Errorf("basket %q: unknown basket %q", name, ref)
+				}
+				return b, err
+			})
+			return b, true, err
+		}
+	}
+
+	if name == FailedBasket {
+		b, err := failedBasket()
+		return b, true, err
+	}
+	return Basket{}, false, nil
+}
+
+// FailedBasket is the name of the basket with the tests not passing in the
+// last session of the test results.
+const FailedBasket = "failed"
+
+// failedBasket returns a basket matching the tests, which did not pass in the
+// last session of the test results.
+func failedBasket() (Basket, error) {
+	db, err := latestResults()
+	if err != nil {
+		return Basket{}, err
+	}
+	var names []string
+	if n := len(db.Sessions); n > 0 {
+		for _, r := range results.FinalVerdicts(db.Sessions[n-1].Runs) {
+			switch r.Verdict {
+			case "pass", "unstable", "skipped":
+			default:
+				name := r.Name
+				if i := strings.Index(name, "("); i >= 0 {
+					name = name[:i]
+				}
+				names = append(names, name)
+			}
+		}
+	}
+	return Basket{Name: FailedBasket, NameRegex: []string{exactRegex(names)}}, nil
+}
+
+// exactRegex returns a regular expression matching exactly the given names.
+// An empty list of names results in an expression matching only the empty
+// string.
+func exactRegex(names []string) string {
+	if len(names) == 0 {
+		return "^$"
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return fmt.Sprintf("^(%s)$", strings.Join(quoted, "|"))
+}
+
 // Match returns true if the given name and tags match the basket or sub-basket filters.
 func (b *Basket) Match(name string, tags [][]string) bool {
 	ok := b.match(name, tags)
@@ -230,6 +422,18 @@ func (b *Basket) match(name string, tags [][]string) bool {
 		return false
 	}
 
+	if len(b.Terms) > 0 {
+		ok := false
+		for _, t := range b.Terms {
+			if t.Basket.Match(name, tags) {
+				ok = !t.Exclude
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -2,10 +2,14 @@ package main
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/nokia/ntt/internal/results"
+	"github.com/nokia/ntt/project"
 	"github.com/nokia/ntt/ttcn3/doc"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestBasketMatch(t *testing.T) {
@@ -142,3 +146,73 @@ func TestLoadFromEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestNamedBaskets(t *testing.T) {
+	conf := &project.Config{}
+	conf.Baskets = map[string]string{
+		"sanity": "/^sanity/",
+		"smoke":  "sanity + @priority: high - @slow",
+		"stable": "-X @wip",
+		"cycle":  "smoke + loop",
+		"loop":   "cycle",
+		"broken": "sanity +",
+	}
+
+	results.Filename = filepath.Join(t.TempDir(), "test_results.json")
+	defer func() { results.Filename = "test_results.json" }()
+	db := results.DB{Sessions: []results.Session{
+		{Runs: []results.Run{{Name: "bar", Verdict: "fail"}}},
+		{Runs: []results.Run{
+			{Name: "foo", Verdict: "fail"},
+			{Name: "foo", Instance: 1, Verdict: "pass"},
+			{Name: "sanity1(23)", Verdict: "error"},
+		}},
+	}}
+	if err := db.WriteFile(results.Filename); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		basket string
+		name   string
+		tags   []string
+		want   bool
+	}{
+		{basket: "sanity", name: "sanity1", want: true},
+		{basket: "sanity", name: "foo", want: false},
+		{basket: "smoke", name: "sanity1", want: true},
+		{basket: "smoke", name: "sanity1", tags: []string{"@slow"}, want: false},
+		{basket: "smoke", name: "foo", tags: []string{"@priority high"}, want: true},
+		{basket: "smoke", name: "foo", tags: []string{"@priority low"}, want: false},
+		{basket: "smoke", name: "foo", tags: []string{"@priority_x high"}, want: false},
+		{basket: "smoke", name: "foo", tags: []string{"@priority high", "@slow"}, want: false},
+		{basket: "stable", name: "foo", want: true},
+		{basket: "stable", name: "foo", tags: []string{"@wip"}, want: false},
+		{basket: "failed", name: "sanity1", want: true},
+		{basket: "failed", name: "foo", want: false},
+		{basket: "failed", name: "bar", want: false},
+	}
+	for _, tt := range tests {
+		var b Basket
+		if err := b.LoadNamed(conf, "NTT_LIST_BASKETS", tt.basket); err != nil {
+			t.Fatal(err)
+		}
+		actual := b.Match(tt.name, doc.FindAllTags(strings.Join(tt.tags, "\n")))
+		if actual != tt.want {
+			t.Errorf("Basket(%q).Match(%q, %q) = %v, want %v", tt.basket, tt.name, tt.tags, actual, tt.want)
+		}
+	}
+
+	var b Basket
+	assert.ErrorContains(t, b.LoadNamed(conf, "NTT_LIST_BASKETS", "cycle"), "cycle cycle -> loop -> cycle")
+	assert.ErrorContains(t, b.LoadNamed(conf, "NTT_LIST_BASKETS", "broken"), "missing term")
+	assert.ErrorIs(t, b.LoadNamed(conf, "NTT_LIST_BASKETS", "unknown"), project.ErrNotFound)
+
+	// Baskets of the manifest are used by NTT_LIST_BASKETS, too.
+	os.Setenv("NTT_LIST_BASKETS", "smoke")
+	defer os.Unsetenv("NTT_LIST_BASKETS")
+	b = Basket{}
+	assert.Nil(t, b.LoadFromEnvOrConfig(conf, "NTT_LIST_BASKETS"))
+	assert.True(t, b.Match("sanity1", nil))
+	assert.False(t, b.Match("foo", nil))
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/project"
+	"github.com/spf13/cobra"
+)
+
+var (
+	CheckConfigCommand = &cobra.Command{
+		Use:   "check-config [DIR|FILE]",
+		Short: "Validate the project configuration",
+		Long: `Validate the project configuration.
+
+The check-config command validates the manifest (package.yml) in DIR (default:
+current directory), or the manifest FILE, the parameters file it refers to and
+all included parameters files. It reports all problems found, each with file
+and line:
+
+  * unknown keys
+  * values of the wrong type
+  * unresolved variable references (${VAR})
+  * sources and imports which do not exist
+  * undefined presets referenced by execute entries
+  * included parameters files, which do not exist
+  * module parameters assigned differently by included files
+
+The --schema flag prints the JSON Schema of the manifest (--schema=manifest)
+or of parameters files (--schema=parameters) instead. Editors may use these
+schemas for completion and validation.
+`,
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{projectArgs: projectArgsSkip},
+		RunE:        checkConfig,
+	}
+
+	schemaKind string
+)
+
+func init() {
+	CheckConfigCommand.Flags().StringVar(&schemaKind, "schema", "", "print JSON Schema of `KIND` (manifest or parameters)")
+	CheckConfigCommand.Flags().Lookup("schema").NoOptDefVal = "manifest"
+}
+
+func checkConfig(cmd *cobra.Command, args []string) error {
+	if schemaKind != "" {
+		return printSchema(schemaKind)
+	}
+
+	file := project.ManifestFile
+	if len(args) > 0 {
+		file = args[0]
+		if fs.IsDir(file) {
+			file = filepath.Join(file, project.ManifestFile)
+		}
+	}
+
+	problems, err := project.CheckManifest(file)
+	if err != nil {
+		return err
+	}
+
+	if outputJSON {
+		if problems == nil {
+			problems = []project.Problem{}
+		}
+		b, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		for _, p := range problems {
+			fmt.Println(p.String())
+		}
+	}
+
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("1 problem found.")
+	default:
+		return fmt.Errorf("%d problems found.", len(problems))
+	}
+}
+
+func printSchema(kind string) error {
+	var (
+		b   []byte
+		err error
+	)
+	switch kind {
+	case "manifest":
+		b, err = project.ManifestSchema()
+	case "parameters":
+		b, err = project.ParametersSchema()
+	default:
+		return fmt.Errorf("unknown schema %q. Use manifest or parameters", kind)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/nokia/ntt/internal/cache"
+	"github.com/nokia/ntt/internal/compdb"
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/project"
+	"github.com/spf13/cobra"
+)
+
+var (
+	CompdbCommand = &cobra.Command{
+		Use:   "compdb [DIR]",
+		Short: "Generate a JSON compilation database",
+		Long: `Generate a JSON compilation database.
+
+The compdb command generates a compilation database (compile_commands.json)
+as specified by https://clang.llvm.org/docs/JSONCompilationDatabase.html.
+
+The database has one entry for every TTCN-3 and ASN.1 source file of every
+test suite found by walking from DIR (default: current directory) towards the
+file system root, and of every test suite listed in the index file
+(ttcn3_suites.json) of the NTT_CACHE directory.
+
+The compiler invocation is configured by the "compiler" field of the
+manifest, followed by diagnostics flags, preprocessor defines, import
+directories and the source file. Without "compiler" field, entries run
+"ntt compile" with the source file only.
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: printCompdb,
+	}
+
+	compdbOutput string
+)
+
+func init() {
+	CompdbCommand.Flags().StringVarP(&compdbOutput, "output", "o", "", "write compilation database to `FILE` instead of stdout")
+}
+
+func printCompdb(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	cmds := []compdb.Command{}
+	for _, s := range discoverSuites(dir) {
+		conf, err := project.NewConfig(
+			project.AutomaticRoot(s.RootDir),
+			project.AutomaticEnv(),
+			project.WithSourceDir(s.SourceDir),
+			project.WithDefaults(),
+		)
+		if err != nil {
+			log.Verbosef("%s: %s\n", s.RootDir, err.Error())
+			continue
+		}
+		cmds = append(cmds, conf.Commands()...)
+	}
+
+	b, err := json.MarshalIndent(cmds, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if compdbOutput != "" {
+		return os.WriteFile(compdbOutput, b, 0644)
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
+
+// discoverSuites returns the test suites found by project.Discover and the
+// test suites listed in the index file of the cache directory.
+func discoverSuites(dir string) []project.Suite {
+	var (
+		list    []project.Suite
+		visited = make(map[string]bool)
+	)
+	add := func(suites ...project.Suite) {
+		for _, s := range suites {
+			if !visited[s.RootDir] {
+				visited[s.RootDir] = true
+				list = append(list, s)
+			}
+		}
+	}
+
+	add(project.Discover(dir)...)
+	if file := cache.Lookup(project.IndexFile); fs.IsRegular(file) {
+		idx, err := project.ReadIndex(file)
+		if err != nil {
+			log.Verbosef("%s\n", err.Error())
+		} else {
+			add(idx.Suites...)
+		}
+	}
+	return list
+}
@@ -0,0 +1,64 @@
+package control
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nokia/ntt/project"
+	"github.com/stretchr/testify/assert"
+)
+
+type nopRunner struct{}
+
+func (nopRunner) Run(context.Context) <-chan Event {
+	ch := make(chan Event)
+	close(ch)
+	return ch
+}
+
+func TestAdmit(t *testing.T) {
+	c, err := New(MaxLoad(2), WithFactory(func() (Runner, error) { return nopRunner{}, nil }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.interval = time.Millisecond
+
+	var loads []float64
+	c.load = func() (float64, error) {
+		l := loads[0]
+		if len(loads) > 1 {
+			loads = loads[1:]
+		}
+		return l, nil
+	}
+	never := func() bool { return false }
+
+	// Jobs are admitted if no other jobs are running.
+	loads = []float64{5}
+	assert.True(t, c.admit(never))
+
+	// Wait until load drops.
+	c.running[&Job{}] = time.Now()
+	loads = []float64{5, 3, 1.5}
+	assert.True(t, c.admit(never))
+	assert.Equal(t, []float64{1.5}, loads)
+
+	// Stop waiting.
+	loads = []float64{5}
+	assert.False(t, c.admit(func() bool { return true }))
+
+	assert.Equal(t, 2, c.Session("s1").MaxLoad)
+	assert.Equal(t, 1, c.Session("s1").MaxJobs)
+}
+
+func TestMaxMemory(t *testing.T) {
+	tp := &TestPlan{conf: &project.Config{}, Tests: []string{"A.TC"}}
+	_, err := New(MaxMemory(1<<20), WithTestPlan(tp), WithFactory(func() (Runner, error) { return nopRunner{}, nil }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, tp.admit)
+	assert.Equal(t, int64(1<<20), tp.Next().MemoryLimit)
+	assert.Nil(t, tp.Next())
+}
@@ -5,6 +5,10 @@ import (
 	"errors"
 	"sync"
 	"time"
+
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/proc"
+	"github.com/nokia/ntt/internal/results"
 )
 
 var ErrNoFactory = errors.New("factory is not set")
@@ -17,12 +21,25 @@ type Runner interface {
 // RunnerFactory creates a new Runner.
 type RunnerFactory func() (Runner, error)
 
-// A Controller executes jobs in parallel.
+// A Controller executes jobs in parallel. Each worker runs the jobs of its
+// own Runner. Runners sharing a TestPlan (see TestPlan.Next) execute the
+// jobs in plan order; use TestPlan.Schedule to start long running tests
+// first.
 type Controller struct {
 	sync.Mutex
 	maxWorkers int
 	running    map[*Job]time.Time
+	loads      map[*Job]float64
 	factory    RunnerFactory
+	plan       *TestPlan
+
+	// runs are the results of all stopped jobs, including retries.
+	runs []results.Run
+
+	maxLoad   int
+	maxMemory int64
+	load      func() (float64, error)
+	interval  time.Duration
 }
 
 // New creates a new Controller.
@@ -30,6 +47,9 @@ func New(opts ...Option) (*Controller, error) {
 	c := &Controller{
 		maxWorkers: 1,
 		running:    make(map[*Job]time.Time),
+		loads:      make(map[*Job]float64),
+		load:       proc.LoadAvg,
+		interval:   5 * time.Second,
 	}
 	for _, opt := range opts {
 		if err := opt(c); err != nil {
@@ -39,9 +59,50 @@ func New(opts ...Option) (*Controller, error) {
 	if c.factory == nil {
 		return nil, ErrNoFactory
 	}
+	if c.plan != nil {
+		c.plan.memoryLimit = c.maxMemory
+		if c.maxLoad > 0 {
+			c.plan.admit = c.admit
+		}
+	}
 	return c, nil
 }
 
+// Session returns a results session with the limits of the controller and
+// the runs of all jobs stopped so far. Every attempt of a retried job is a
+// separate run instance. Callers executing tests append the session to the
+// results file after the run.
+func (c *Controller) Session(id string) results.Session {
+	c.Lock()
+	defer c.Unlock()
+	runs := make([]results.Run, len(c.runs))
+	copy(runs, c.runs)
+	return results.Session{
+		Id:      id,
+		MaxJobs: c.maxWorkers,
+		MaxLoad: c.maxLoad,
+		Runs:    runs,
+	}
+}
+
+// admit blocks while the system load exceeds the load limit and other jobs
+// are running. It returns false if stopped returns true while waiting.
+func (c *Controller) admit(stopped func() bool) bool {
+	for !stopped() {
+		c.Lock()
+		active := len(c.running)
+		c.Unlock()
+
+		load, err := c.load()
+		if err != nil || active == 0 || load <= float64(c.maxLoad) {
+			return true
+		}
+		log.Debugf("load %.2f exceeds limit %d. Waiting for %d running jobs.\n", load, c.maxLoad, active)
+		time.Sleep(c.interval)
+	}
+	return false
+}
+
 func (c *Controller) Run(ctx context.Context) <-chan Event {
 	wg := sync.WaitGroup{}
 	results := make(chan Event, c.maxWorkers)
@@ -65,8 +126,14 @@ func (c *Controller) Run(ctx context.Context) <-chan Event {
 	go func() {
 		const secs = time.Duration(30.0)
 		ticker := time.NewTicker(secs * time.Second)
+		done := ctx.Done()
 		for {
 			select {
+			case <-done:
+				if c.plan != nil {
+					c.plan.Close()
+				}
+				done = nil
 			case res, ok := <-results:
 				if !ok {
 					close(out)
@@ -75,13 +142,55 @@ func (c *Controller) Run(ctx context.Context) <-chan Event {
 				ticker.Reset(secs * time.Second)
 				switch ev := res.(type) {
 				case StartEvent:
+					c.Lock()
 					c.running[ev.Job] = ev.Time()
+					c.Unlock()
+					if load, err := c.load(); err == nil {
+						c.loads[ev.Job] = load
+					}
 				case StopEvent:
+					c.Lock()
 					ev.Begin = c.running[ev.Job]
+					delete(c.running, ev.Job)
+					ev.Load = c.loads[ev.Job]
+					delete(c.loads, ev.Job)
+					if ev.Job != nil && ev.Job.Config != nil {
+						ev.Quarantined = ev.Job.IsQuarantined(ev.Name)
+					}
+					c.runs = append(c.runs, ev.Result())
+					c.Unlock()
+					if c.plan != nil && ev.Job != nil {
+						c.plan.Done(ev.Job, ev.Verdict)
+					}
+					res = ev
+				case ErrorEvent:
+					if job := UnwrapJob(ev); job != nil {
+						// Record jobs aborted by errors,
+						// unless they have been stopped
+						// already.
+						c.Lock()
+						if begin, ok := c.running[job]; ok {
+							c.runs = append(c.runs, abortedRun(job, begin, ev))
+						}
+						delete(c.running, job)
+						c.Unlock()
+						delete(c.loads, job)
+						if c.plan != nil {
+							c.plan.Done(job, "error")
+						}
+					} else if c.plan != nil {
+						c.plan.release()
+					}
 				}
 				out <- res
 			case <-ticker.C:
+				c.Lock()
+				var jobs []*Job
 				for job := range c.running {
+					jobs = append(jobs, job)
+				}
+				c.Unlock()
+				for _, job := range jobs {
 					out <- NewTickerEvent(job)
 				}
 			}
@@ -97,6 +206,19 @@ func (c *Controller) Run(ctx context.Context) <-chan Event {
 	return out
 }
 
+// abortedRun returns the test run of a job aborted by an error.
+func abortedRun(job *Job, begin time.Time, ev ErrorEvent) results.Run {
+	return results.Run{
+		Name:       job.Name,
+		Instance:   job.Instance,
+		Verdict:    "error",
+		Reason:     ev.Err.Error(),
+		Begin:      results.Timestamp{Time: begin},
+		End:        results.Timestamp{Time: ev.Time()},
+		WorkingDir: job.Dir,
+	}
+}
+
 type Option func(*Controller) error
 
 func MaxWorkers(n int) Option {
@@ -106,6 +228,41 @@ func MaxWorkers(n int) Option {
 	}
 }
 
+// MaxLoad pauses starting new jobs of the test plan (see WithTestPlan) while
+// the system load exceeds n. Zero means no limit.
+//
+// The limit only applies to runners taking their jobs from the test plan
+// using TestPlan.Next. Without test plan the limit is only recorded in the
+// session (see Session).
+func MaxLoad(n int) Option {
+	return func(c *Controller) error {
+		c.maxLoad = n
+		return nil
+	}
+}
+
+// MaxMemory limits the memory of every job of the test plan (see
+// WithTestPlan) to the given number of bytes. Zero means no limit.
+//
+// The limit is stored in Job.MemoryLimit. It is enforced for processes
+// started using StartProcess.
+func MaxMemory(bytes int64) Option {
+	return func(c *Controller) error {
+		c.maxMemory = bytes
+		return nil
+	}
+}
+
+// WithTestPlan reports stopped jobs to the given test plan, which enables
+// retries of failed jobs. Runners are expected to take their jobs from the
+// same test plan.
+func WithTestPlan(tp *TestPlan) Option {
+	return func(c *Controller) error {
+		c.plan = tp
+		return nil
+	}
+}
+
 func WithFactory(f RunnerFactory) Option {
 	return func(c *Controller) error {
 		c.factory = f
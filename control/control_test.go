@@ -0,0 +1,210 @@
+package control_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nokia/ntt/control"
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/results"
+	"github.com/nokia/ntt/internal/yaml"
+	"github.com/nokia/ntt/project"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRunner runs the given jobs by emitting a start and a stop event with
+// the verdict fail.
+type fakeRunner struct {
+	jobs []*control.Job
+}
+
+func (r *fakeRunner) Run(ctx context.Context) <-chan control.Event {
+	ch := make(chan control.Event)
+	go func() {
+		defer close(ch)
+		for _, job := range r.jobs {
+			ch <- control.NewStartEvent(job, job.Name)
+			ch <- control.NewStopEvent(job, job.Name, "fail")
+		}
+	}()
+	return ch
+}
+
+func TestControllerQuarantine(t *testing.T) {
+	conf := &project.Config{}
+	conf.Quarantine = []string{"A.*"}
+	jobs := []*control.Job{
+		control.NewJob("A.TC1", conf),
+		control.NewJob("B.TC1", conf),
+	}
+
+	c, err := control.New(control.WithFactory(func() (control.Runner, error) {
+		return &fakeRunner{jobs: jobs}, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quarantined := make(map[string]bool)
+	for ev := range c.Run(context.Background()) {
+		if ev, ok := ev.(control.StopEvent); ok {
+			quarantined[ev.Name] = ev.Quarantined
+			assert.False(t, ev.Begin.IsZero())
+		}
+	}
+	assert.Equal(t, map[string]bool{"A.TC1": true, "B.TC1": false}, quarantined)
+}
+
+// planRunner runs the jobs of a test plan. The verdict of a job is provided
+// by the verdict function.
+type planRunner struct {
+	plan    *control.TestPlan
+	verdict func(*control.Job) string
+}
+
+func (r *planRunner) Run(ctx context.Context) <-chan control.Event {
+	ch := make(chan control.Event)
+	go func() {
+		defer close(ch)
+		for job := r.plan.Next(); job != nil; job = r.plan.Next() {
+			ch <- control.NewStartEvent(job, job.Name)
+			ch <- control.NewStopEvent(job, job.Name, r.verdict(job))
+		}
+	}()
+	return ch
+}
+
+func TestControllerRetry(t *testing.T) {
+	fs.SetContent("test://retry.ttcn3", []byte(`module A {
+		testcase flaky() {}
+		testcase broken() {}
+		testcase passing() {}
+
+		// @wip
+		testcase wip() {}
+	}`))
+	conf := &project.Config{}
+	conf.Sources = []string{"test://retry.ttcn3"}
+	conf.Execute = []project.TestConfig{
+		{
+			Test:  "A.*",
+			Rules: project.Rules{Except: &project.ExecuteCondition{Tags: []string{"@wip"}}},
+			Retry: &project.RetryPolicy{Max: 2, Backoff: yaml.Duration{Duration: 10 * time.Millisecond}},
+		},
+	}
+	tp, err := control.NewTestPlan(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verdict := func(job *control.Job) string {
+		switch {
+		case job.Name == "A.flaky" && job.Instance == 1:
+			return "pass"
+		case job.Name == "A.passing":
+			return "pass"
+		default:
+			return "fail"
+		}
+	}
+	c, err := control.New(
+		control.MaxWorkers(2),
+		control.WithTestPlan(tp),
+		control.WithFactory(func() (control.Runner, error) {
+			return &planRunner{plan: tp, verdict: verdict}, nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range c.Run(context.Background()) {
+	}
+	runs := c.Session("retry").Runs
+
+	var ids []string
+	for _, r := range runs {
+		ids = append(ids, r.Verdict+" "+r.ID())
+	}
+	assert.ElementsMatch(t, []string{
+		"fail A.flaky-0",
+		"pass A.flaky-1",
+		"fail A.broken-0",
+		"fail A.broken-1",
+		"fail A.broken-2",
+		"pass A.passing-0",
+		"fail A.wip-0",
+	}, ids)
+
+	verdicts := make(map[string]string)
+	for _, r := range results.FinalVerdicts(runs) {
+		verdicts[r.Name] = r.Verdict
+	}
+	assert.Equal(t, map[string]string{
+		"A.flaky":   "unstable",
+		"A.broken":  "fail",
+		"A.passing": "pass",
+		"A.wip":     "fail",
+	}, verdicts)
+}
+
+// failingRunner takes a job from the test plan and fails without telling
+// which job was affected.
+type failingRunner struct {
+	plan *control.TestPlan
+}
+
+func (r *failingRunner) Run(ctx context.Context) <-chan control.Event {
+	ch := make(chan control.Event)
+	go func() {
+		defer close(ch)
+		if job := r.plan.Next(); job != nil {
+			ch <- control.NewErrorEvent(errors.New("runner crashed"))
+		}
+	}()
+	return ch
+}
+
+func TestControllerRunnerError(t *testing.T) {
+	fs.SetContent("test://runner_error.ttcn3", []byte(`module A {
+		testcase tc1() {}
+		testcase tc2() {}
+	}`))
+	conf := &project.Config{}
+	conf.Sources = []string{"test://runner_error.ttcn3"}
+	conf.Execute = []project.TestConfig{
+		{Test: "A.*", Retry: &project.RetryPolicy{Max: 1}},
+	}
+	tp, err := control.NewTestPlan(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var created int32
+	c, err := control.New(
+		control.MaxWorkers(2),
+		control.WithTestPlan(tp),
+		control.WithFactory(func() (control.Runner, error) {
+			if atomic.AddInt32(&created, 1) == 1 {
+				return &failingRunner{plan: tp}, nil
+			}
+			return &planRunner{plan: tp, verdict: func(*control.Job) string { return "pass" }}, nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range c.Run(context.Background()) {
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("test plan blocked by job of failed runner")
+	}
+}
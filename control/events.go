@@ -4,6 +4,8 @@ package control
 import (
 	"errors"
 	"time"
+
+	"github.com/nokia/ntt/internal/results"
 )
 
 // Event provides information regarding test execution.
@@ -53,6 +55,18 @@ type StopEvent struct {
 	Name    string
 	Verdict string
 	Begin   time.Time
+
+	// Quarantined is true if the test is in the quarantine list of the
+	// job configuration. Failures of quarantined tests should not fail
+	// the test run.
+	Quarantined bool
+
+	// Load is the system load when the test was started.
+	Load float64
+
+	// MaxMem is the maximum memory in bytes used by the test, if
+	// provided by the runner (see NewProcessStopEvent).
+	MaxMem int
 	event
 	*Job
 }
@@ -62,6 +76,24 @@ func NewStopEvent(job *Job, name string, verdict string) StopEvent {
 	return StopEvent{event: event{t: time.Now()}, Job: job, Name: name, Verdict: verdict}
 }
 
+// Result returns the test run described by the stop event. Retries of a job
+// result in runs with the same name but different instances.
+func (e StopEvent) Result() results.Run {
+	r := results.Run{
+		Name:    e.Name,
+		Verdict: e.Verdict,
+		Begin:   results.Timestamp{Time: e.Begin},
+		End:     results.Timestamp{Time: e.Time()},
+		Load:    e.Load,
+		MaxMem:  e.MaxMem,
+	}
+	if e.Job != nil {
+		r.Instance = e.Job.Instance
+		r.WorkingDir = e.Job.Dir
+	}
+	return r
+}
+
 // TickerEvent is an event that is emitted periodically during the test execution.
 type TickerEvent struct {
 	event
@@ -15,6 +15,13 @@ type Job struct {
 	// Name is the fully qualified name of the test or control function.
 	Name string
 
+	// Instance counts the attempts to execute the job, starting with 0.
+	// Retries of a job have the same name but a higher instance.
+	Instance int
+
+	// Retry describes if and how the job is repeated after failure.
+	Retry *project.RetryPolicy
+
 	// Args is the list of arguments to pass to the test.
 	Args []string
 
@@ -30,6 +37,11 @@ type Job struct {
 	// Env specifies the environment variables to pass to the job.
 	Env []string
 
+	// MemoryLimit is the maximum memory in bytes the job may use. Zero
+	// means no limit. Runners enforce the limit by starting the processes
+	// of jobs using StartProcess.
+	MemoryLimit int64
+
 	// Config provides the project configuration
 	*project.Config
 }
@@ -25,8 +25,11 @@ func (p *ConsolePrinter) Print(ev control.Event) {
 	case control.TickerEvent:
 		ColorRunning.Printf("... active %s\n", ev.Name)
 	case control.StopEvent:
-		c := Colors(ev.Verdict)
-		c.Printf("--- %s %s\t(duration=%.2fs)\n", ev.Verdict, ev.Name, ev.Time().Sub(ev.Begin).Seconds())
+		note := ""
+		if quarantined(ev) {
+			note = " (quarantined)"
+		}
+		stopColor(ev).Printf("--- %s %s%s\t(duration=%.2fs)\n", ev.Verdict, ev.Name, note, ev.Time().Sub(ev.Begin).Seconds())
 	case control.ErrorEvent:
 		msg := fmt.Sprintf("+++ fatal ")
 		if job := control.UnwrapJob(ev); job != nil {
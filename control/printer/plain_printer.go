@@ -22,8 +22,7 @@ func (p *PlainPrinter) Print(ev control.Event) {
 	case control.StartEvent:
 	case control.TickerEvent:
 	case control.StopEvent:
-		c := Colors(ev.Verdict)
-		c.Printf("%s\t%s\t%.2f\n", ev.Verdict, ev.Name, ev.Time().Sub(ev.Begin).Seconds())
+		stopColor(ev).Printf("%s\t%s\t%.2f\n", ev.Verdict, ev.Name, ev.Time().Sub(ev.Begin).Seconds())
 	case control.ErrorEvent:
 		msg := fmt.Sprintf("error: ")
 		if job := control.UnwrapJob(ev); job != nil {
@@ -2,8 +2,11 @@ package printer
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/fatih/color"
+	"github.com/mattn/go-colorable"
+	"github.com/mattn/go-isatty"
 	"github.com/nokia/ntt/control"
 )
 
@@ -33,6 +36,46 @@ var (
 	ErrCommandFailed = fmt.Errorf("command failed")
 )
 
+// quarantined returns true if the stop event reports a failure of a
+// quarantined test. Such failures are printed, but do not fail the test run.
+func quarantined(ev control.StopEvent) bool {
+	return ev.Quarantined && ev.Verdict != "pass" && ev.Verdict != "done"
+}
+
+// stopColor returns the color for printing a stop event.
+func stopColor(ev control.StopEvent) *color.Color {
+	if quarantined(ev) {
+		return ColorWarning
+	}
+	return Colors(ev.Verdict)
+}
+
 type Printer interface {
 	Print(ev control.Event)
 }
+
+// New returns a printer for the given output format: "json", "plain", "tap",
+// "progress" or "text". Total is the number of expected tests or 0 if
+// unknown. Commands executing tests with a control.Controller are expected to
+// print its events using a printer returned by New.
+//
+// The progress printer requires a terminal. If stdout is not a terminal,
+// plain line output is used instead. Printers implementing io.Closer must be
+// closed after the last event.
+func New(format string, total int) Printer {
+	switch format {
+	case "json":
+		return NewJSONPrinter()
+	case "plain":
+		return NewPlainPrinter()
+	case "tap":
+		return NewTAPPrinter()
+	case "progress":
+		if isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd()) {
+			return NewProgressPrinter(colorable.NewColorableStdout(), total)
+		}
+		return NewPlainPrinter()
+	default:
+		return NewConsolePrinter()
+	}
+}
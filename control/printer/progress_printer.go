@@ -0,0 +1,273 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/nokia/ntt/control"
+)
+
+// ProgressPrinter is a terminal front-end for test execution. It shows a
+// progress bar with verdict counters, the running jobs with their elapsed
+// time and the last log messages. Tests not passing are printed above the
+// progress display, so they remain visible after the test run.
+type ProgressPrinter struct {
+	// Width is the width of the terminal.
+	Width int
+
+	// MaxJobs is the maximum number of running jobs to display.
+	MaxJobs int
+
+	// MaxLogs is the number of log lines to display.
+	MaxLogs int
+
+	mu      sync.Mutex
+	w       io.Writer
+	total   int
+	begin   time.Time
+	now     func() time.Time
+	running map[runKey]time.Time
+	logs    []string
+	lines   int
+	done    int
+	passed  int
+	failed  int
+	other   int
+
+	// quarantined counts failures of quarantined tests.
+	quarantined int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+type runKey struct {
+	job  *control.Job
+	name string
+}
+
+// NewProgressPrinter returns a progress printer writing to terminal w. Total
+// is the number of expected tests or 0 if unknown. The display is refreshed
+// periodically until Close is called.
+func NewProgressPrinter(w io.Writer, total int) *ProgressPrinter {
+	p := newProgressPrinter(w, total, time.Now)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.mu.Lock()
+				p.redraw()
+				p.mu.Unlock()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+func newProgressPrinter(w io.Writer, total int, now func() time.Time) *ProgressPrinter {
+	width := 80
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 20 {
+		width = n
+	}
+	return &ProgressPrinter{
+		Width:   width,
+		MaxJobs: 10,
+		MaxLogs: 3,
+		w:       w,
+		total:   total,
+		begin:   now(),
+		now:     now,
+		running: make(map[runKey]time.Time),
+		stop:    make(chan struct{}),
+	}
+}
+
+func (p *ProgressPrinter) Print(ev control.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var permanent []string
+	switch ev := ev.(type) {
+	case control.LogEvent:
+		for _, line := range strings.Split(strings.TrimRightFunc(ev.Text, unicode.IsSpace), "\n") {
+			p.logs = append(p.logs, line)
+		}
+		if len(p.logs) > p.MaxLogs {
+			p.logs = p.logs[len(p.logs)-p.MaxLogs:]
+		}
+	case control.StartEvent:
+		p.running[runKey{ev.Job, ev.Name}] = ev.Time()
+	case control.TickerEvent:
+	case control.StopEvent:
+		delete(p.running, runKey{ev.Job, ev.Name})
+		if quarantined(ev) {
+			p.done++
+			p.quarantined++
+			permanent = append(permanent, stopColor(ev).Sprintf("--- %s %s (quarantined)\t(duration=%.2fs)", ev.Verdict, ev.Name, ev.Time().Sub(ev.Begin).Seconds()))
+			break
+		}
+		p.count(ev.Verdict)
+		if ev.Verdict != "pass" {
+			permanent = append(permanent, Colors(ev.Verdict).Sprintf("--- %s %s\t(duration=%.2fs)", ev.Verdict, ev.Name, ev.Time().Sub(ev.Begin).Seconds()))
+		}
+	case control.ErrorEvent:
+		msg := "+++ fatal "
+		if job := control.UnwrapJob(ev); job != nil {
+			msg += job.Name + ": "
+			// Count jobs aborted by errors, unless they have
+			// been stopped already.
+			for k := range p.running {
+				if k.job == job {
+					delete(p.running, k)
+					p.count("error")
+				}
+			}
+		}
+		permanent = append(permanent, ColorFatal.Sprint(msg+ev.Err.Error()))
+	default:
+		panic(fmt.Sprintf("event type %T not implemented", ev))
+	}
+	p.redraw(permanent...)
+}
+
+// Close stops refreshing the display and prints a summary.
+func (p *ProgressPrinter) Close() error {
+	close(p.stop)
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	fmt.Fprintf(p.w, "%d tests: %s in %s\n", p.done, p.counters(), p.now().Sub(p.begin).Round(time.Second))
+	return nil
+}
+
+// counters returns the verdict counters. Quarantined failures are only
+// mentioned if there are any.
+func (p *ProgressPrinter) counters() string {
+	s := fmt.Sprintf("%d passed, %d failed, %d other", p.passed, p.failed, p.other)
+	if p.quarantined > 0 {
+		s += fmt.Sprintf(", %d quarantined", p.quarantined)
+	}
+	return s
+}
+
+func (p *ProgressPrinter) count(verdict string) {
+	p.done++
+	switch verdict {
+	case "pass":
+		p.passed++
+	case "fail", "error":
+		p.failed++
+	default:
+		p.other++
+	}
+}
+
+// redraw replaces the progress display. The given lines are printed above
+// the display.
+func (p *ProgressPrinter) redraw(permanent ...string) {
+	p.clear()
+	for _, line := range permanent {
+		fmt.Fprintln(p.w, line)
+	}
+	lines := p.render()
+	for _, line := range lines {
+		fmt.Fprintln(p.w, line)
+	}
+	p.lines = len(lines)
+}
+
+// clear removes the progress display by moving the cursor up and clearing
+// the rest of the screen.
+func (p *ProgressPrinter) clear() {
+	if p.lines > 0 {
+		fmt.Fprintf(p.w, "\x1b[%dA\r\x1b[J", p.lines)
+		p.lines = 0
+	}
+}
+
+// render returns the lines of the progress display.
+func (p *ProgressPrinter) render() []string {
+	now := p.now()
+	status := fmt.Sprintf("%s  %s", p.counters(), formatElapsed(now.Sub(p.begin)))
+	if p.total > 0 {
+		status = fmt.Sprintf("%d/%d %3d%%  %s", p.done, p.total, 100*p.done/p.total, status)
+		if n := p.Width - len(status) - 3; n >= 10 {
+			status = progressBar(n, p.done, p.total) + " " + status
+		}
+	} else {
+		status = fmt.Sprintf("%d done  %s", p.done, status)
+	}
+	lines := []string{p.truncate(status)}
+
+	type job struct {
+		name  string
+		begin time.Time
+	}
+	var jobs []job
+	for k, begin := range p.running {
+		jobs = append(jobs, job{k.name, begin})
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].begin.Equal(jobs[j].begin) {
+			return jobs[i].begin.Before(jobs[j].begin)
+		}
+		return jobs[i].name < jobs[j].name
+	})
+	for i, j := range jobs {
+		if i == p.MaxJobs {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(jobs)-i))
+			break
+		}
+		elapsed := formatElapsed(now.Sub(j.begin))
+		name := j.name
+		if n := p.Width - len(elapsed) - 3; len(name) > n && n > 0 {
+			name = name[:n]
+		}
+		lines = append(lines, ColorRunning.Sprintf("  %-*s %s", p.Width-len(elapsed)-3, name, elapsed))
+	}
+
+	for _, l := range p.logs {
+		lines = append(lines, ColorLog.Sprint(p.truncate("# "+l)))
+	}
+	return lines
+}
+
+func (p *ProgressPrinter) truncate(s string) string {
+	if len(s) > p.Width {
+		return s[:p.Width]
+	}
+	return s
+}
+
+// progressBar returns a progress bar of the given width.
+func progressBar(width, done, total int) string {
+	n := width - 2
+	filled := n * done / total
+	if filled > n {
+		filled = n
+	}
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", n-filled) + "]"
+}
+
+func formatElapsed(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Hour {
+		return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
@@ -0,0 +1,77 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nokia/ntt/control"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressPrinter(t *testing.T) {
+	// Events use the real clock.
+	now := time.Now()
+	var buf bytes.Buffer
+	p := newProgressPrinter(&buf, 4, func() time.Time { return now })
+	p.Width = 60
+
+	a := control.NewJob("A.TC1", nil)
+	b := control.NewJob("A.TC2", nil)
+	c := control.NewJob("A.TC3", nil)
+	p.Print(control.NewStartEvent(a, a.Name))
+	p.Print(control.NewStartEvent(b, b.Name))
+	p.Print(control.NewStartEvent(c, c.Name))
+	p.Print(control.NewLogEvent(a, "hello\nworld\n"))
+	now = now.Add(65 * time.Second)
+
+	stop := control.NewStopEvent(a, a.Name, "fail")
+	stop.Begin = now.Add(-2 * time.Second)
+	p.Print(stop)
+	p.Print(control.NewErrorEvent(&control.JobError{Job: c, Err: errors.New("crashed")}))
+
+	assert.Equal(t, []string{
+		"[=====      ] 2/4  50%  0 passed, 2 failed, 0 other  01:05",
+		"  A.TC2                                                01:05",
+		"# hello",
+		"# world",
+	}, p.render())
+
+	buf.Reset()
+	p.Print(control.NewStopEvent(b, b.Name, "pass"))
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "\x1b[4A\r\x1b[J"), out)
+
+	buf.Reset()
+	assert.Nil(t, p.Close())
+	assert.Equal(t, "\x1b[3A\r\x1b[J3 tests: 1 passed, 2 failed, 0 other in 1m5s\n", buf.String())
+}
+
+func TestProgressPrinterQuarantine(t *testing.T) {
+	now := time.Now()
+	var buf bytes.Buffer
+	p := newProgressPrinter(&buf, 0, func() time.Time { return now })
+
+	a := control.NewJob("A.TC1", nil)
+	stop := control.NewStopEvent(a, a.Name, "fail")
+	stop.Begin = now
+	stop.Quarantined = true
+	p.Print(stop)
+	assert.Contains(t, buf.String(), "--- fail A.TC1 (quarantined)")
+
+	pass := control.NewStopEvent(a, "A.TC2", "pass")
+	pass.Quarantined = true
+	p.Print(pass)
+
+	buf.Reset()
+	assert.Nil(t, p.Close())
+	assert.Equal(t, "\x1b[1A\r\x1b[J2 tests: 1 passed, 0 failed, 0 other, 1 quarantined in 0s\n", buf.String())
+}
+
+func TestFormatElapsed(t *testing.T) {
+	assert.Equal(t, "00:05", formatElapsed(5*time.Second))
+	assert.Equal(t, "59:01", formatElapsed(59*time.Minute+time.Second))
+	assert.Equal(t, "1:01:01", formatElapsed(61*time.Minute+time.Second))
+}
@@ -9,9 +9,10 @@ import (
 )
 
 type TAPPrinter struct {
-	n       int
-	success int
-	failed  int
+	n           int
+	success     int
+	failed      int
+	quarantined int
 }
 
 func NewTAPPrinter() *TAPPrinter {
@@ -24,21 +25,26 @@ func (p *TAPPrinter) Print(ev control.Event) {
 		fmt.Printf("# %s\n", strings.ReplaceAll(strings.TrimRightFunc(ev.Text, unicode.IsSpace), "\n", "\n# "))
 	case control.StartEvent:
 		p.n++
-		fmt.Printf("# %s (%s): started\n", ev.Name, ev.ID)
+		fmt.Printf("# %s: started\n", ev.Name)
 	case control.TickerEvent:
 	case control.StopEvent:
-		verdict := "not ok"
-		if verdict == "pass" || verdict == "done" {
-			verdict = "ok"
+		// Failures of quarantined tests are reported as TODO tests,
+		// which TAP consumers do not count as failures.
+		switch {
+		case ev.Verdict == "pass" || ev.Verdict == "done":
 			p.success++
-		} else {
+			fmt.Printf("ok %d - %s\n", p.count(), ev.Name)
+		case quarantined(ev):
+			p.quarantined++
+			fmt.Printf("not ok %d - %s # TODO quarantined\n", p.count(), ev.Name)
+		default:
 			p.failed++
+			fmt.Printf("not ok %d - %s\n", p.count(), ev.Name)
 		}
-		fmt.Printf("%s %d - %s\n", verdict, p.n, ev.ID)
 	case control.ErrorEvent:
 		jobID := ""
 		if job := control.UnwrapJob(ev); job != nil {
-			jobID = " " + job.ID + ":"
+			jobID = " " + job.Name + ":"
 		}
 		fmt.Printf("#%s error: %s\n", jobID, ev.Error())
 	default:
@@ -50,12 +56,20 @@ func (p *TAPPrinter) Close() error {
 	switch {
 	case p.n == 0:
 		fmt.Println("1..0 # SKIP no tests")
-	case p.success == p.n && p.failed == 0:
+	case p.success+p.quarantined == p.n && p.failed == 0:
 		fmt.Printf("# passed all %d tests.\n", p.n)
+		if p.quarantined > 0 {
+			fmt.Printf("# ignored %d failures of quarantined tests.\n", p.quarantined)
+		}
 		fmt.Printf("1..%d\n", p.n)
 	default:
-		fmt.Printf("# failed %d among %d tests.\n", p.n-p.success, p.n)
+		fmt.Printf("# failed %d among %d tests.\n", p.n-p.success-p.quarantined, p.n)
 		fmt.Printf("1..%d\n", p.n)
 	}
 	return nil
 }
+
+// count returns the number of stopped tests.
+func (p *TAPPrinter) count() int {
+	return p.success + p.failed + p.quarantined
+}
@@ -0,0 +1,44 @@
+package control
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/proc"
+)
+
+// StartProcess starts cmd as the process executing the job and applies the
+// memory limit of the job to it. Runners starting processes for jobs should
+// use StartProcess instead of cmd.Start.
+//
+// The limit is applied right after the process has been started. On
+// platforms without memory limits a message is logged and the process runs
+// without limit.
+func StartProcess(job *Job, cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if job == nil || job.MemoryLimit <= 0 {
+		return nil
+	}
+	err := proc.SetMemoryLimit(cmd.Process.Pid, uint64(job.MemoryLimit))
+	switch {
+	case errors.Is(err, proc.ErrNotSupported):
+		log.Verbosef("%s: memory limit %s\n", job.Name, err.Error())
+	case err != nil:
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fmt.Errorf("%s: memory limit: %w", job.Name, err)
+	}
+	return nil
+}
+
+// NewProcessStopEvent is like NewStopEvent, but additionally records the
+// maximum memory used by the exited process of the job.
+func NewProcessStopEvent(job *Job, name string, verdict string, state *os.ProcessState) StopEvent {
+	return StopEvent{event: event{t: time.Now()}, Job: job, Name: name, Verdict: verdict, MaxMem: proc.MaxRSS(state)}
+}
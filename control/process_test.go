@@ -0,0 +1,34 @@
+package control_test
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/nokia/ntt/control"
+	"github.com/nokia/ntt/internal/proc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartProcess(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("memory limits are only supported on linux")
+	}
+	job := control.NewJob("A.TC1", nil)
+	job.MemoryLimit = 1 << 30
+
+	cmd := proc.Command("sleep", "0.2")
+	if err := control.StartProcess(job, cmd); err != nil {
+		t.Skip(err)
+	}
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/limits", cmd.Process.Pid))
+	assert.Nil(t, err)
+	assert.True(t, strings.Contains(string(b), "1073741824"), string(b))
+	assert.Nil(t, cmd.Wait())
+
+	ev := control.NewProcessStopEvent(job, job.Name, "pass", cmd.ProcessState)
+	assert.Greater(t, ev.MaxMem, 0)
+	assert.Equal(t, ev.MaxMem, ev.Result().MaxMem)
+}
@@ -0,0 +1,108 @@
+package control
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Durations maps fully qualified test names to their expected duration, for
+// example computed from previous test runs.
+type Durations map[string]time.Duration
+
+// Expected returns the expected duration of the given test. Tests without
+// known duration are expected to take the mean duration of all known tests.
+func (d Durations) Expected(name string) time.Duration {
+	return d.expected()(name)
+}
+
+// expected returns a function computing expected durations. The mean is
+// calculated only once.
+func (d Durations) expected() func(string) time.Duration {
+	var mean time.Duration
+	if len(d) > 0 {
+		var sum time.Duration
+		for _, v := range d {
+			sum += v
+		}
+		mean = sum / time.Duration(len(d))
+	}
+	return func(name string) time.Duration {
+		if v, ok := d[name]; ok {
+			return v
+		}
+		return mean
+	}
+}
+
+// Schedule sorts the tests of the test plan by expected duration, longest
+// first. Starting long tests first reduces the total run time when the tests
+// are executed by multiple workers. Tests with equal duration keep their
+// order.
+func (tp *TestPlan) Schedule(d Durations) {
+	sortByDuration(tp.Tests, d)
+}
+
+// Shard splits the tests of the test plan into n shards of similar expected
+// run time and keeps only the tests of the i-th shard (counting from 1).
+// The split is deterministic: the same tests and durations result in the same
+// shards. Tests of the shard are scheduled longest first.
+func (tp *TestPlan) Shard(i, n int, d Durations) error {
+	if n < 1 || i < 1 || i > n {
+		return fmt.Errorf("invalid shard %d/%d", i, n)
+	}
+
+	tests := make([]string, len(tp.Tests))
+	copy(tests, tp.Tests)
+	sortByDuration(tests, d)
+	expected := d.expected()
+
+	// Assign each test to the shard with the least expected run time
+	// (longest processing time first).
+	totals := make([]time.Duration, n)
+	var shard []string
+	for _, t := range tests {
+		min := 0
+		for k := range totals {
+			if totals[k] < totals[min] {
+				min = k
+			}
+		}
+		totals[min] += expected(t)
+
+		// Without any durations all tests would land in the first
+		// shard. Distribute them round robin instead.
+		if expected(t) == 0 {
+			totals[min]++
+		}
+		if min == i-1 {
+			shard = append(shard, t)
+		}
+	}
+	tp.Tests = shard
+	return nil
+}
+
+// ParseShard parses a shard specification of the form "i/n".
+func ParseShard(s string) (i, n int, err error) {
+	f := strings.SplitN(s, "/", 2)
+	if len(f) == 2 {
+		i, err = strconv.Atoi(strings.TrimSpace(f[0]))
+		if err == nil {
+			n, err = strconv.Atoi(strings.TrimSpace(f[1]))
+		}
+		if err == nil && n >= 1 && i >= 1 && i <= n {
+			return i, n, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid shard %q: expected i/n with 1 <= i <= n", s)
+}
+
+func sortByDuration(tests []string, d Durations) {
+	expected := d.expected()
+	sort.SliceStable(tests, func(i, j int) bool {
+		return expected(tests[i]) > expected(tests[j])
+	})
+}
@@ -0,0 +1,94 @@
+package control_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nokia/ntt/control"
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPlan(t *testing.T, src string) *control.TestPlan {
+	fs.SetContent("test://schedule.ttcn3", []byte(src))
+	tp, err := control.NewTestPlan(&project.Config{
+		Manifest: project.Manifest{Sources: []string{"test://schedule.ttcn3"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tp
+}
+
+func TestSchedule(t *testing.T) {
+	tp := newPlan(t, `module m {
+		testcase a() {}
+		testcase b() {}
+		testcase c() {}
+		testcase d() {}
+	}`)
+	tp.Schedule(control.Durations{
+		"m.a": 1 * time.Second,
+		"m.b": 5 * time.Second,
+		"m.d": 2 * time.Second,
+	})
+	// Unknown m.c is expected to take the mean of 2.666s.
+	assert.Equal(t, []string{"m.b", "m.c", "m.d", "m.a"}, tp.Tests)
+
+	var jobs []string
+	for job := tp.Next(); job != nil; job = tp.Next() {
+		jobs = append(jobs, job.Name)
+	}
+	assert.Equal(t, tp.Tests, jobs)
+}
+
+func TestShard(t *testing.T) {
+	src := `module m {
+		testcase a() {}
+		testcase b() {}
+		testcase c() {}
+		testcase d() {}
+		testcase e() {}
+	}`
+	d := control.Durations{
+		"m.a": 7 * time.Second,
+		"m.b": 5 * time.Second,
+		"m.c": 4 * time.Second,
+		"m.d": 3 * time.Second,
+		"m.e": 1 * time.Second,
+	}
+
+	var shards [][]string
+	for i := 1; i <= 2; i++ {
+		tp := newPlan(t, src)
+		assert.Nil(t, tp.Shard(i, 2, d))
+		shards = append(shards, tp.Tests)
+	}
+	assert.Equal(t, [][]string{
+		{"m.a", "m.d"},        // 10s
+		{"m.b", "m.c", "m.e"}, // 10s
+	}, shards)
+
+	// Without durations tests are distributed round robin.
+	shards = nil
+	for i := 1; i <= 3; i++ {
+		tp := newPlan(t, src)
+		assert.Nil(t, tp.Shard(i, 3, nil))
+		shards = append(shards, tp.Tests)
+	}
+	assert.Equal(t, [][]string{{"m.a", "m.d"}, {"m.b", "m.e"}, {"m.c"}}, shards)
+
+	assert.NotNil(t, newPlan(t, src).Shard(3, 2, d))
+}
+
+func TestParseShard(t *testing.T) {
+	i, n, err := control.ParseShard("2/4")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2, 4}, []int{i, n})
+
+	for _, s := range []string{"", "2", "0/4", "5/4", "a/b", "1/0"} {
+		_, _, err := control.ParseShard(s)
+		assert.NotNil(t, err, s)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/nokia/ntt/internal/log"
 	"github.com/nokia/ntt/project"
 	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/doc"
 	"github.com/nokia/ntt/ttcn3/syntax"
 )
 
@@ -108,6 +109,25 @@ type TestPlan struct {
 	conf *project.Config
 	m    sync.Map
 
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pos     int
+	closed  bool
+	retries []*Job
+
+	// pending are the jobs, which might be retried.
+	pending map[*Job]bool
+
+	// waiting is the number of retries waiting for their backoff delay.
+	waiting int
+
+	// admit blocks until a job may be started. It returns false if
+	// the job must not be started anymore.
+	admit func(stopped func() bool) bool
+
+	// memoryLimit is the memory limit of each job in bytes.
+	memoryLimit int64
+
 	// Controls is a ordered list of fully qualified control functions.
 	Controls []string
 
@@ -115,13 +135,169 @@ type TestPlan struct {
 	Tests []string
 }
 
-// Next returns the next Job to be executed. If there are no more jobs, nil is
-// returned.
+// Next returns the next Job to be executed. Jobs are returned in the order
+// of Tests, retries of failed jobs first. If there are no more jobs, nil is
+// returned. Next is safe to be called by multiple runners concurrently.
+//
+// Jobs with a retry policy must be reported using Done. Until then Next
+// blocks instead of returning nil, because a retry might become necessary.
+//
+// When the test plan is executed by a Controller with a load limit, Next
+// blocks while the system load exceeds the limit.
 func (tp *TestPlan) Next() *Job {
+	job := tp.take()
+	if job == nil || tp.admit == nil {
+		return job
+	}
+	if !tp.admit(tp.isClosed) {
+		tp.Done(job, "")
+		return nil
+	}
+	return job
+}
+
+// take removes the next job from the test plan.
+func (tp *TestPlan) take() *Job {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	for !tp.closed {
+		var job *Job
+		switch {
+		case len(tp.retries) > 0:
+			job = tp.retries[0]
+			tp.retries = tp.retries[1:]
+		case tp.pos < len(tp.Tests):
+			job = tp.newJob(tp.Tests[tp.pos])
+			tp.pos++
+		case len(tp.pending) == 0 && tp.waiting == 0:
+			return nil
+		default:
+			tp.wait()
+			continue
+		}
+		if job.Retry != nil && job.Instance < job.Retry.Max {
+			if tp.pending == nil {
+				tp.pending = make(map[*Job]bool)
+			}
+			tp.pending[job] = true
+		}
+		return job
+	}
 	return nil
 }
 
+// Done reports the verdict of a job returned by Next. If the retry policy
+// of the job demands it, a retry with incremented instance is scheduled after
+// the backoff delay.
+func (tp *TestPlan) Done(job *Job, verdict string) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	if !tp.pending[job] {
+		return
+	}
+	delete(tp.pending, job)
+	defer tp.signal()
+
+	if tp.closed || !job.Retry.ShouldRetry(verdict, job.Instance) {
+		return
+	}
+	retry := *job
+	retry.Instance++
+	log.Verbosef("retrying %s (%d/%d) after verdict %s\n", retry.Name, retry.Instance, retry.Retry.Max, verdict)
+
+	tp.waiting++
+	time.AfterFunc(retry.Retry.Delay(retry.Instance), func() {
+		tp.mu.Lock()
+		defer tp.mu.Unlock()
+		tp.waiting--
+		tp.retries = append(tp.retries, &retry)
+		tp.signal()
+	})
+}
+
+// Close stops the test plan. Subsequent calls of Next return nil.
+func (tp *TestPlan) Close() {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.closed = true
+	tp.signal()
+}
+
+func (tp *TestPlan) isClosed() bool {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return tp.closed
+}
+
+// release gives up retrying the pending jobs. It is used when a runner failed
+// without telling which job was affected, because such a job would never be
+// reported using Done and block Next forever.
+func (tp *TestPlan) release() {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	if len(tp.pending) == 0 {
+		return
+	}
+	log.Verbosef("not retrying %d pending jobs after runner error\n", len(tp.pending))
+	tp.pending = nil
+	tp.signal()
+}
+
+// newJob returns a job for the given test with the retry policy of its test
+// configuration. Test configurations are matched by name and by the tags of
+// the testcase documentation.
+func (tp *TestPlan) newJob(name string) *Job {
+	job := NewJob(name, tp.conf)
+	job.MemoryLimit = tp.memoryLimit
+	t := project.Test{Name: name}
+	if v, ok := tp.m.Load(name); ok {
+		t.Tags = doc.FindAllTags(syntax.Doc(v.(syntax.Node)))
+	}
+	tcs, err := tp.conf.TestConfigsFor(t)
+	if err != nil {
+		log.Debugf("%s: %s\n", name, err.Error())
+	}
+	for _, tc := range tcs {
+		if tc.Retry != nil {
+			job.Retry = tc.Retry
+			break
+		}
+	}
+	return job
+}
+
+// wait waits for a signal. The mutex must be held.
+func (tp *TestPlan) wait() {
+	if tp.cond == nil {
+		tp.cond = sync.NewCond(&tp.mu)
+	}
+	tp.cond.Wait()
+}
+
+// signal wakes all runners waiting in Next. The mutex must be held.
+func (tp *TestPlan) signal() {
+	if tp.cond != nil {
+		tp.cond.Broadcast()
+	}
+}
+
 // Add adds the given test case to the test plan.
 func (tp *TestPlan) Add(name string) error {
 	return fmt.Errorf("%w: %s", ErrNoSuch, name)
 }
+
+// Filter removes all tests from the test plan for which keep returns false.
+// The node passed to keep is the syntax node of the test case definition.
+func (tp *TestPlan) Filter(keep func(name string, n syntax.Node) bool) {
+	var tests []string
+	for _, name := range tp.Tests {
+		var n syntax.Node
+		if v, ok := tp.m.Load(name); ok {
+			n = v.(syntax.Node)
+		}
+		if keep(name, n) {
+			tests = append(tests, name)
+		}
+	}
+	tp.Tests = tests
+}
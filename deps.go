@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/project"
+	"github.com/spf13/cobra"
+)
+
+var (
+	DepsCommand = &cobra.Command{
+		Use:   "deps",
+		Short: "Manage dependencies",
+		Long: `Manage dependencies.
+
+Dependencies are TTCN-3 libraries specified in the dependencies section of
+the manifest (package.yml). A dependency is either a local directory, a git
+repository at a tag, branch or commit, or a tarball:
+
+	dependencies:
+	  common:
+	    path: ../common
+	  adapters:
+	    git: https://example.com/adapters.git
+	    ref: v1.2.0
+	  codecs:
+	    url: https://example.com/codecs-2.0.tar.gz
+	    sha256: 4f2a...
+
+Resolved revisions and content hashes are recorded in the lock file
+(package.lock) next to the manifest. Dependencies are fetched into the deps
+directory of NTT_CACHE, or of the user cache directory if NTT_CACHE is not
+set. Fetched and vendored dependencies are appended to the imports of the
+project automatically.
+`,
+	}
+
+	DepsFetchCommand = &cobra.Command{
+		Use:   "fetch",
+		Short: "Fetch dependencies at the revisions recorded in the lock file",
+		Long: `Fetch dependencies at the revisions recorded in the lock file.
+
+Dependencies missing in the lock file or whose source changed are resolved and
+added to the lock file. The content of every dependency, including local
+dependencies, is verified against the hash recorded in the lock file. Use ntt
+deps update NAME to accept changes of a local dependency.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := fetchDependencies(false)
+			return err
+		},
+	}
+
+	DepsUpdateCommand = &cobra.Command{
+		Use:   "update [NAME...]",
+		Short: "Resolve dependencies again and update the lock file",
+		Long: `Resolve dependencies again and update the lock file.
+
+Without arguments all dependencies are updated. Otherwise only the named
+dependencies are updated.
+`,
+		Annotations: map[string]string{projectArgs: projectArgsNone},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := fetchDependencies(len(args) == 0, args...)
+			return err
+		},
+	}
+
+	DepsVendorCommand = &cobra.Command{
+		Use:   "vendor",
+		Short: "Copy dependencies into the vendor directory of the project",
+		Long: `Copy dependencies into the vendor directory of the project.
+
+Dependencies are fetched like with ntt deps fetch and copied into the vendor
+directory next to the manifest. Vendored dependencies take precedence over
+fetched dependencies. Local dependencies are not vendored.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lock, err := fetchDependencies(false)
+			if err != nil {
+				return err
+			}
+			return project.VendorDependencies(Project, lock)
+		},
+	}
+)
+
+func init() {
+	DepsCommand.AddCommand(DepsFetchCommand, DepsUpdateCommand, DepsVendorCommand)
+}
+
+// fetchDependencies fetches the dependencies of the project and writes the
+// lock file.
+func fetchDependencies(all bool, update ...string) (*project.Lock, error) {
+	if Project.ManifestFile == "" {
+		return nil, fmt.Errorf("dependencies require a manifest (%s)", project.ManifestFile)
+	}
+	for _, name := range update {
+		if _, ok := Project.Dependencies[name]; !ok {
+			return nil, fmt.Errorf("unknown dependency %q", name)
+		}
+	}
+
+	file := fs.JoinPath(Project.Root, project.LockFile)
+	lock, err := project.ReadLock(file)
+	if err != nil {
+		return nil, err
+	}
+	lock, err = project.FetchDependencies(Project, lock, all, update...)
+	if err != nil {
+		return nil, err
+	}
+	return lock, lock.Write(file)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/project"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ExportCommand = &cobra.Command{
+		Use:   "export",
+		Short: "Export project configuration for other tools",
+		Long: `Export project configuration for other tools.
+
+The --titan flag generates Eclipse Titan project descriptors (.tpd) and a
+Makefile fragment, which allow building the test suite with the open source
+Titan toolchain:
+
+  <name>.tpd   project descriptor of the test suite.
+  <lib>.tpd    one project descriptor for each import directory, referenced
+               by <name>.tpd.
+  <name>.mk    Makefile fragment listing all modules, preprocessor defines
+               and the executable name, using makefilegen variables.
+
+Files are written into the current directory, unless --output-dir is given.
+`,
+		RunE: export,
+	}
+
+	exportTitan bool
+	exportDir   string
+)
+
+func init() {
+	ExportCommand.Flags().BoolVarP(&exportTitan, "titan", "", false, "export Eclipse Titan project descriptors and Makefile fragment")
+	ExportCommand.Flags().StringVarP(&exportDir, "output-dir", "o", ".", "write files into `DIR`")
+}
+
+func export(cmd *cobra.Command, args []string) error {
+	if !exportTitan {
+		return errors.New("no export format given. Use --titan")
+	}
+
+	files, err := project.ExportTitan(Project, exportDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(exportDir, 0755); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(exportDir, name)
+		if err := os.WriteFile(path, files[name], 0644); err != nil {
+			return err
+		}
+		log.Println(path)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/extract"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ExtractCommand = &cobra.Command{
+		Use:   "extract TESTCASE",
+		Short: "Extract the definitions required by a testcase",
+		Long: `Extract the definitions required by a testcase.
+
+The extract command computes all definitions a testcase requires, directly
+or indirectly, and prints a stripped-down set of modules containing only
+those definitions. Compiling the extracted modules is usually much faster
+than compiling the whole test suite, which helps reproducing single test
+failures.
+
+The testcase is given as last argument, either by name or by qualified name.
+Preceding arguments specify the test suite like for any other command.
+Example:
+
+	ntt extract -o /tmp/repro test.tc_foo
+	ntt extract path/to/suite tc_foo
+
+Imports of the extracted modules are replaced by "import from X all", because
+explicit import lists might refer to definitions not extracted. Groups are
+not preserved.
+`,
+		Args:        cobra.MinimumNArgs(1),
+		Annotations: map[string]string{projectArgs: projectArgsAllButLast},
+		RunE:        extractTestcase,
+	}
+
+	extractDir string
+)
+
+func init() {
+	ExtractCommand.Flags().StringVarP(&extractDir, "output-dir", "o", "", "write each module into a separate file in `DIR`")
+}
+
+func extractTestcase(cmd *cobra.Command, args []string) error {
+	files, err := project.Files(Project)
+	if err != nil {
+		return err
+	}
+	tc, err := findTestcase(files, args[len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	var db ttcn3.DB
+	db.Index(files...)
+
+	if extractDir != "" {
+		if err := os.MkdirAll(extractDir, 0755); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range extract.Closure(&db, tc) {
+		var buf bytes.Buffer
+		if err := extract.Fprint(&buf, m); err != nil {
+			return err
+		}
+		b := formatExtracted(buf.Bytes())
+		if extractDir == "" {
+			os.Stdout.Write(b)
+			continue
+		}
+		path := filepath.Join(extractDir, m.Name+".ttcn3")
+		if err := os.WriteFile(path, b, 0644); err != nil {
+			return err
+		}
+		log.Verboseln(path)
+	}
+	return nil
+}
+
+// formatExtracted formats the output of the TTCN-3 printer. If formatting
+// fails, the source is returned unchanged.
+func formatExtracted(src []byte) []byte {
+	var buf bytes.Buffer
+	p, err := newFormatter(&buf, Project)
+	if err != nil {
+		return src
+	}
+	p.Indent = -1
+	if err := p.Fprint(src); err != nil {
+		log.Debugf("extract: %s\n", err.Error())
+		return src
+	}
+	return buf.Bytes()
+}
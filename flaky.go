@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nokia/ntt/internal/results"
+	"github.com/spf13/cobra"
+)
+
+var (
+	FlakyCommand = &cobra.Command{
+		Use:   "flaky",
+		Short: "List flaky tests",
+		Long: `List flaky tests.
+
+The flaky command computes a flakiness score for every test recorded in the
+test history (see ntt report --record) and lists the tests with the highest
+scores first.
+
+The score is the fraction of sessions, in which a test behaved flaky: either
+its verdict was unstable within the session, or it changed between passing and
+not passing compared to the previous session.
+
+Flaky tests may be added to the quarantine list of the manifest or parameters
+file. Quarantined tests are still executed, but their failures do not fail
+the test run:
+
+	quarantine:
+	  - mymodule.TC_flaky
+	  - othermodule.*
+
+Quarantined tests are marked with an asterisk.
+`,
+		Args: cobra.NoArgs,
+		RunE: flaky,
+	}
+
+	flakySince    string
+	flakyLimit    int
+	flakyMinScore float64
+)
+
+func init() {
+	FlakyCommand.Flags().StringVar(&flakySince, "since", "", "only consider sessions recorded since `TIME`")
+	FlakyCommand.Flags().IntVarP(&flakyLimit, "limit", "n", 20, "list at most `N` tests (0 for all)")
+	FlakyCommand.Flags().Float64Var(&flakyMinScore, "min-score", 0.01, "list only tests with a score of at least `SCORE`")
+}
+
+func flaky(cmd *cobra.Command, args []string) error {
+	h := results.OpenHistory(historyFile())
+
+	var (
+		entries []results.Entry
+		err     error
+	)
+	if flakySince != "" {
+		var t time.Time
+		t, err = results.ParseSince(flakySince, time.Now())
+		if err != nil {
+			return err
+		}
+		entries, err = h.Since(t)
+	} else {
+		entries, err = h.Entries()
+	}
+	if err != nil {
+		return err
+	}
+
+	sessions := make([]results.Session, len(entries))
+	for i, e := range entries {
+		sessions[i] = e.Session
+	}
+
+	list := []results.Flakiness{}
+	for _, f := range results.FlakinessScores(sessions...) {
+		if f.Score < flakyMinScore || (flakyLimit > 0 && len(list) >= flakyLimit) {
+			break
+		}
+		list = append(list, f)
+	}
+
+	if outputJSON {
+		b, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	if outputPlain {
+		for _, f := range list {
+			fmt.Printf("%.2f\t%s\n", f.Score, f.Name)
+		}
+		return nil
+	}
+
+	if len(list) == 0 {
+		fmt.Printf("no flaky tests found in %d sessions\n", len(sessions))
+		return nil
+	}
+	fmt.Printf("%-6s %-8s %-6s %-8s %s\n", "SCORE", "UNSTABLE", "FLIPS", "SESSIONS", "TEST")
+	for _, f := range list {
+		name := f.Name
+		if Project.IsQuarantined(name) {
+			name += " *"
+		}
+		fmt.Printf("%-6.2f %-8d %-6d %-8d %s\n", f.Score, f.Unstable, f.Flips, f.Sessions, name)
+	}
+	return nil
+}
@@ -3,11 +3,13 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/project"
 	printer "github.com/nokia/ntt/ttcn3/format"
 	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
@@ -37,8 +39,24 @@ Formatting style is a matter of taste and a subject of debate. A canonical
 style settles such debates and makes code easier to read due to a consistent
 style across projects and teams.
 
-For this reason, the format command does not support custom confirguration like
-maximum line length or indentation style.
+For this reason, the default style only fixes indentation and white-space and
+does not support custom configuration.
+
+
+PRETTY STYLE
+
+The pretty style (--style=pretty) additionally:
+
+  * wraps parameter lists, argument lists and composite literals (e.g.
+    template bodies), which exceed the line width, one element per line.
+  * aligns the := of field assignments in composite literals.
+  * separates module definitions by exactly one blank line.
+
+The style and the line width may also be configured in the manifest:
+
+	format:
+	  style: pretty
+	  line_width: 100
 
 
 EXIT STATUS
@@ -57,15 +75,22 @@ this tool. Do not use it in production yet.
 `,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			srcs, err := fs.TTCN3Files(Project.Sources...)
+			groups, err := suiteGroups(func(c *project.Config) ([]string, error) {
+				return fs.TTCN3Files(c.Sources...)
+			})
 			if err != nil {
 				return err
 			}
 
 			var merr *multierror.Error
-			for _, src := range srcs {
-				if err := processFile(src); err != nil {
-					merr = multierror.Append(merr, err)
+			for _, g := range groups {
+				if listFiles || diff {
+					printSuiteHeader(os.Stdout, g)
+				}
+				for _, src := range g.Files {
+					if err := processFile(src, g.Suite); err != nil {
+						merr = multierror.Append(merr, err)
+					}
 				}
 			}
 
@@ -80,23 +105,60 @@ this tool. Do not use it in production yet.
 	listFiles, diff, inplace bool
 	formattedFiles           int
 	spaces                   int
+	formatStyle              string
+	lineWidth                int
 )
 
+// DefaultLineWidth is the line width used by the pretty style, if not
+// configured otherwise.
+const DefaultLineWidth = 100
+
 func init() {
 	FormatCommand.Flags().BoolVarP(&inplace, "in-place", "i", false, "format files in place")
 	FormatCommand.Flags().BoolVarP(&diff, "diff", "d", false, "display diff instead of rewriting files. Exit with non-zero status if any files need to be formatted")
 	FormatCommand.Flags().BoolVarP(&listFiles, "list", "l", false, "list files whose formatting differs. Exit with non-zero status if any files need to be formatted")
 	FormatCommand.Flags().IntVarP(&spaces, "tabs-to-spaces", "s", 0, "convert each tab to N spaces")
+	FormatCommand.Flags().StringVarP(&formatStyle, "style", "", "", "formatting style: canonical or pretty")
+	FormatCommand.Flags().IntVarP(&lineWidth, "line-width", "w", 0, "maximum line width for pretty style")
 }
 
-func processFile(path string) error {
+// newFormatter returns a printer configured by command line flags and the
+// format section of the manifest of conf. Command line flags take precedence.
+func newFormatter(w io.Writer, conf *project.Config) (*printer.CanonicalPrinter, error) {
+	style, width := formatStyle, lineWidth
+	if conf != nil {
+		if style == "" {
+			style = conf.Format.Style
+		}
+		if width == 0 {
+			width = conf.Format.LineWidth
+		}
+	}
+	if width == 0 {
+		width = DefaultLineWidth
+	}
+
+	switch style {
+	case "", "canonical":
+		return printer.NewCanonicalPrinter(w), nil
+	case "pretty":
+		return printer.NewPrettyPrinter(w, width), nil
+	default:
+		return nil, fmt.Errorf("unknown formatting style %q", style)
+	}
+}
+
+func processFile(path string, conf *project.Config) error {
 	src, err := fs.Content(path)
 	if err != nil {
 		return err
 	}
 
 	var buf bytes.Buffer
-	p := printer.NewCanonicalPrinter(&buf)
+	p, err := newFormatter(&buf, conf)
+	if err != nil {
+		return err
+	}
 	p.Indent = -1
 	if spaces > 0 {
 		p.UseSpaces = true
@@ -105,8 +167,13 @@ func processFile(path string) error {
 	if err := p.Fprint(src); err != nil {
 		return err
 	}
-	res := buf.Bytes()
+	return writeResult(path, src, buf.Bytes())
+}
 
+// writeResult outputs the result of processing the file path according to the
+// --in-place, --diff and --list flags. Without any of these flags res is
+// written to stdout.
+func writeResult(path string, src, res []byte) error {
 	if !bytes.Equal(src, res) {
 
 		formattedFiles++
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/graph"
+	"github.com/spf13/cobra"
+)
+
+var (
+	GraphCommand = &cobra.Command{
+		Use:   "graph",
+		Short: "Print the module import graph",
+		Long: `Print the module import graph.
+
+The graph command builds the import graph of all modules of the project,
+including the modules from imported directories, and prints it in graphviz
+DOT format (default), JSON (--json) or as Mermaid flowchart (--mermaid).
+Example:
+
+	ntt graph | dot -Tsvg > graph.svg
+
+Modules involved in import cycles are highlighted.
+
+
+Cycles
+------
+
+The --cycles flag prints the strongly connected components of the graph,
+which form import cycles, one per line. The command exits with non-zero
+status if the graph contains cycles.
+
+
+Testcases
+---------
+
+The --testcase flag restricts the graph to the modules a testcase requires:
+the module defining the testcase and all modules it imports, directly or
+indirectly. The testcase may be given by its name or by its qualified name.
+Example:
+
+	ntt graph --plain --testcase=test.tc_foo
+
+With --plain only the list of required module names is printed.
+`,
+		RunE: printGraph,
+	}
+
+	outputMermaid bool
+	graphCycles   bool
+	graphTestcase string
+)
+
+func init() {
+	flags := GraphCommand.Flags()
+	flags.BoolVarP(&outputDot, "dot", "", false, "graphviz output (default)")
+	flags.BoolVarP(&outputMermaid, "mermaid", "", false, "Mermaid flowchart output")
+	flags.BoolVarP(&graphCycles, "cycles", "", false, "print import cycles")
+	flags.StringVarP(&graphTestcase, "testcase", "", "", "only print modules required by testcase `NAME`")
+}
+
+func printGraph(cmd *cobra.Command, args []string) error {
+	files, err := project.Files(Project)
+	if err != nil {
+		return err
+	}
+	g := graph.New(files...)
+
+	if graphTestcase != "" {
+		mod, err := testcaseModule(files, graphTestcase)
+		if err != nil {
+			return err
+		}
+		g = g.Subgraph(g.Deps(mod)...)
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	cycles := g.Cycles()
+	switch {
+	case graphCycles && !outputJSON:
+		for _, c := range cycles {
+			fmt.Fprintln(w, strings.Join(c, " "))
+		}
+	case outputJSON:
+		b, err := json.MarshalIndent(struct {
+			Modules []*graph.Module `json:"modules"`
+			Cycles  [][]string      `json:"cycles,omitempty"`
+		}{
+			Modules: modules(g),
+			Cycles:  cycles,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		w.Write(b)
+		w.WriteString("\n")
+	case outputPlain:
+		for _, name := range g.Names() {
+			fmt.Fprintln(w, name)
+		}
+	case outputMermaid:
+		mermaid(w, g, cycles)
+	default:
+		dotGraph(w, g, cycles)
+	}
+
+	if graphCycles && len(cycles) > 0 {
+		return fmt.Errorf("%d import cycles", len(cycles))
+	}
+	return nil
+}
+
+// testcaseModule returns the name of the module defining the given testcase.
+func testcaseModule(files []string, name string) (string, error) {
+	tc, err := findTestcase(files, name)
+	if err != nil {
+		return "", err
+	}
+	return ttcn3.ModuleName(tc.Tree.QualifiedName(tc.Node)), nil
+}
+
+// findTestcase returns the testcase with the given name or qualified name.
+func findTestcase(files []string, name string) (*ttcn3.Node, error) {
+	var (
+		found []*ttcn3.Node
+		mods  []string
+	)
+	for _, file := range files {
+		tree := ttcn3.ParseFile(file)
+		for _, tc := range tree.Tests() {
+			if qname := tree.QualifiedName(tc.Node); qname == name || tc.Ident.String() == name {
+				found = append(found, tc)
+				mods = append(mods, ttcn3.ModuleName(qname))
+			}
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("testcase %q not found", name)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("testcase %q is ambiguous: found in modules %s", name, strings.Join(mods, ", "))
+	}
+}
+
+func modules(g *graph.Graph) []*graph.Module {
+	var ret []*graph.Module
+	for _, name := range g.Names() {
+		ret = append(ret, g.Modules[name])
+	}
+	return ret
+}
+
+// inCycle maps all modules which are part of a cycle to the (one-based)
+// index of their cycle.
+func inCycle(cycles [][]string) map[string]int {
+	ret := make(map[string]int)
+	for i, c := range cycles {
+		for _, name := range c {
+			ret[name] = i + 1
+		}
+	}
+	return ret
+}
+
+func dotGraph(w *bufio.Writer, g *graph.Graph, cycles [][]string) {
+	red := inCycle(cycles)
+	w.WriteString(`digraph {
+	rankdir=LR
+`)
+	for _, name := range g.Names() {
+		m := g.Modules[name]
+		props := []string{fmt.Sprintf("label=\"%s\"", name)}
+		if m.File == "" {
+			props = append(props, "style=dashed")
+		}
+		if red[name] > 0 {
+			props = append(props, "color=red")
+		}
+		fmt.Fprintf(w, "\t%q [%s];\n", name, strings.Join(props, "; "))
+	}
+	for _, name := range g.Names() {
+		for _, dep := range g.Modules[name].Imports {
+			if red[name] > 0 && red[name] == red[dep] {
+				fmt.Fprintf(w, "\t%q -> %q [color=red];\n", name, dep)
+				continue
+			}
+			fmt.Fprintf(w, "\t%q -> %q;\n", name, dep)
+		}
+	}
+	w.WriteString("}\n")
+}
+
+func mermaid(w *bufio.Writer, g *graph.Graph, cycles [][]string) {
+	red := inCycle(cycles)
+	w.WriteString("flowchart LR\n")
+	for _, name := range g.Names() {
+		fmt.Fprintf(w, "\t%s[\"%s\"]\n", name, name)
+		if red[name] > 0 {
+			fmt.Fprintf(w, "\tclass %s cycle\n", name)
+		}
+	}
+	for _, name := range g.Names() {
+		for _, dep := range g.Modules[name].Imports {
+			fmt.Fprintf(w, "\t%s --> %s\n", name, dep)
+		}
+	}
+	if len(cycles) > 0 {
+		w.WriteString("\tclassDef cycle stroke:red\n")
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/results"
+)
+
+var (
+	recordHistory bool
+	sinceTime     string
+	compareRef    string
+)
+
+const CompareTemplate = `{{bold}}Compared with {{.Baseline}}{{off}}
+{{with .NewlyFailing}}
+{{red}}Newly failing ({{len .}}):{{off}}
+{{range .}}  {{ printf "%-10s %s" .Verdict .Name | colorize }}
+{{end}}{{end}}{{with .NewlyPassing}}
+{{green}}Newly passing ({{len .}}):{{off}}
+{{range .}}  {{ printf "%-10s %s" .Verdict .Name | colorize }}
+{{end}}{{end}}{{with .NewlyFlaky}}
+{{orange}}Newly flaky ({{len .}}):{{off}}
+{{range .}}  {{ printf "%-10s %s" .Verdict .Name | colorize }}
+{{end}}{{end}}{{with .Regressions}}
+{{bold}}Duration regressions ({{len .}}):{{off}}
+{{range .}}  {{ printf "%-40s %10s -> %s" .Name .Before .After }}
+{{end}}{{end}}{{if .Unchanged}}
+no changes
+{{end}}`
+
+// A HistoryReport compares the latest test results with sessions recorded in
+// the history.
+type HistoryReport struct {
+	results.Comparison
+
+	// Baseline describes the sessions compared with.
+	Baseline string `json:"baseline"`
+}
+
+// Unchanged returns true if the comparison found no changes.
+func (r HistoryReport) Unchanged() bool {
+	return len(r.NewlyFailing)+len(r.NewlyPassing)+len(r.NewlyFlaky)+len(r.Regressions) == 0
+}
+
+// resultsFile returns the path of the test results file of the project.
+func resultsFile() string {
+	if Project != nil && Project.ResultsFile != "" {
+		return Project.ResultsFile
+	}
+	return results.Filename
+}
+
+// latestResults reads the test results file of the project.
+func latestResults() (*results.DB, error) {
+	return results.ReadFile(resultsFile())
+}
+
+// historyFile returns the path of the history file, which is located next to
+// the results file.
+func historyFile() string {
+	return fs.JoinPath(filepath.Dir(resultsFile()), results.HistoryFilename)
+}
+
+// recordSessions appends the sessions of the latest test results to the
+// history.
+func recordSessions(db *results.DB) error {
+	commit := gitCommit(Project.Root)
+	var entries []results.Entry
+	for _, s := range db.Sessions {
+		entries = append(entries, results.NewEntry(s, commit))
+	}
+	return results.OpenHistory(historyFile()).Append(entries...)
+}
+
+// compareHistory compares the latest test results with a baseline from the
+// history: either the session given by --compare or all sessions recorded
+// since --since.
+func compareHistory(w io.Writer, db *results.DB) error {
+	h := results.OpenHistory(historyFile())
+
+	// Sessions of the latest test results are no baseline.
+	current := make(map[string]bool)
+	for _, s := range db.Sessions {
+		current[s.Id] = true
+	}
+
+	var (
+		baseline []results.Entry
+		desc     string
+	)
+	switch {
+	case compareRef == "previous":
+		entries, err := h.Entries()
+		if err != nil {
+			return err
+		}
+		for i := len(entries) - 1; i >= 0; i-- {
+			if !current[entries[i].Id] {
+				baseline = entries[i : i+1]
+				break
+			}
+		}
+		if baseline == nil {
+			return fmt.Errorf("no previous session found in %s", h.Path)
+		}
+		desc = describeEntry(baseline[0])
+	case compareRef != "":
+		e, err := h.Lookup(compareRef)
+		if err != nil {
+			return err
+		}
+		baseline = []results.Entry{e}
+		desc = describeEntry(e)
+	default:
+		t, err := results.ParseSince(sinceTime, time.Now())
+		if err != nil {
+			return err
+		}
+		entries, err := h.Since(t)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if !current[e.Id] {
+				baseline = append(baseline, e)
+			}
+		}
+		desc = fmt.Sprintf("%d sessions since %s", len(baseline), t.Format("2006-01-02 15:04"))
+	}
+
+	r := HistoryReport{
+		Comparison: results.Compare(results.EntryRuns(baseline...), db.Runs()),
+		Baseline:   desc,
+	}
+
+	if useJSON {
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+
+	tmpl, err := template.New("ntt-compare-template").Funcs(funcMap).Parse(CompareTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, r)
+}
+
+func describeEntry(e results.Entry) string {
+	s := fmt.Sprintf("session %s (%s", e.Id, e.Timestamp.Format("2006-01-02 15:04"))
+	if e.Commit != "" {
+		s += ", commit " + shortCommit(e.Commit)
+	}
+	return s + ")"
+}
+
+func shortCommit(s string) string {
+	if len(s) > 12 {
+		return s[:12]
+	}
+	return s
+}
+
+// gitCommit returns the commit checked out in dir or an empty string if dir
+// is not in a git repository.
+func gitCommit(dir string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		log.Debugf("git rev-parse: %s\n", err.Error())
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
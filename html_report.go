@@ -0,0 +1,337 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/syntax"
+)
+
+// An HTMLReport is the data passed to HTMLTemplate.
+type HTMLReport struct {
+	*Report
+	Generated time.Time
+	Pie       template.HTML
+	Histogram template.HTML
+	Timeline  template.HTML
+	Modules   []HTMLModule
+}
+
+// An HTMLModule is a collection of the test runs of one module.
+type HTMLModule struct {
+	*Collection
+	Pie  template.HTML
+	Rows []HTMLTest
+}
+
+// An HTMLTest is a test with its final verdict and its source location.
+type HTMLTest struct {
+	Run
+	Location string
+	URL      template.URL
+	Reasons  []File
+}
+
+// writeHTMLReport writes a self-contained HTML report of the latest test run.
+func writeHTMLReport(w io.Writer) error {
+	r, err := NewReport(Project)
+	if err != nil {
+		return err
+	}
+
+	locs := testLocations(Project)
+	tests := r.Tests()
+	h := HTMLReport{
+		Report:    r,
+		Generated: time.Now(),
+		Pie:       verdictPie(tests, 120),
+		Histogram: durationHistogram(tests),
+		Timeline:  loadTimeline(r.Runs()),
+	}
+
+	for _, m := range r.Modules() {
+		hm := HTMLModule{Collection: m, Pie: verdictPie(m.Tests(), 48)}
+		for _, t := range m.Tests() {
+			ht := HTMLTest{Run: t}
+			if span, ok := locs[t.Name]; ok {
+				ht.Location = fmt.Sprintf("%s:%d", span.Filename, span.Begin.Line)
+				ht.URL = template.URL((&url.URL{Scheme: "file", Path: span.Filename, Fragment: fmt.Sprintf("L%d", span.Begin.Line)}).String())
+			}
+			if files, err := t.ReasonFiles(); err == nil {
+				ht.Reasons = files
+			}
+			hm.Rows = append(hm.Rows, ht)
+		}
+		sort.Slice(hm.Rows, func(i, j int) bool { return hm.Rows[i].Name < hm.Rows[j].Name })
+		h.Modules = append(h.Modules, hm)
+	}
+	sort.Slice(h.Modules, func(i, j int) bool { return h.Modules[i].Name < h.Modules[j].Name })
+
+	tmpl, err := template.New("ntt-html-report").Funcs(template.FuncMap{
+		"verdictColor": verdictColor,
+		"seconds":      func(d time.Duration) string { return fmt.Sprintf("%.3fs", d.Seconds()) },
+	}).Parse(HTMLTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, h)
+}
+
+// testLocations returns the source locations of all testcases of the suite,
+// keyed by qualified name.
+func testLocations(suite *project.Config) map[string]syntax.Span {
+	locs := make(map[string]syntax.Span)
+	files, err := project.Files(suite)
+	if err != nil {
+		log.Verbosef("html report: %s\n", err.Error())
+		return locs
+	}
+
+	var db ttcn3.DB
+	db.Index(files...)
+	for module, files := range db.Modules {
+		for file := range files {
+			tree := ttcn3.ParseFile(file)
+			for _, n := range tree.Tests() {
+				if name := tree.QualifiedName(n.Node); strings.HasPrefix(name, module+".") {
+					span := syntax.SpanOf(n.Node)
+					if abs, err := filepath.Abs(span.Filename); err == nil {
+						span.Filename = abs
+					}
+					locs[name] = span
+				}
+			}
+		}
+	}
+	return locs
+}
+
+var verdictOrder = []string{"pass", "unstable", "quarantined", "skipped", "none", "inconc", "fail", "error"}
+
+func verdictColor(verdict string) string {
+	switch verdict {
+	case "pass":
+		return "#2e9e44"
+	case "unstable":
+		return "#f08c00"
+	case "quarantined":
+		return "#fcc419"
+	case "skipped":
+		return "#adb5bd"
+	case "none", "inconc":
+		return "#be4bdb"
+	case "fail":
+		return "#e03131"
+	default:
+		return "#862e2e"
+	}
+}
+
+// verdictPie returns an SVG pie chart of the verdicts of the given tests.
+func verdictPie(tests RunSlice, size int) template.HTML {
+	counts := make(map[string]int)
+	for _, t := range tests {
+		counts[t.Verdict]++
+	}
+	verdicts := append([]string{}, verdictOrder...)
+	for v := range counts {
+		if !contains(verdicts, v) {
+			verdicts = append(verdicts, v)
+		}
+	}
+
+	r := float64(size) / 2
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="pie" width="%d" height="%d" viewBox="0 0 %d %d">`, size, size, size, size)
+	if len(tests) == 0 {
+		fmt.Fprintf(&b, `<circle cx="%g" cy="%g" r="%g" fill="#e9ecef"/>`, r, r, r)
+	}
+	angle := 0.0
+	for _, v := range verdicts {
+		n := counts[v]
+		if n == 0 {
+			continue
+		}
+		title := fmt.Sprintf("<title>%s: %d</title>", template.HTMLEscapeString(v), n)
+		if n == len(tests) {
+			fmt.Fprintf(&b, `<circle cx="%g" cy="%g" r="%g" fill="%s">%s</circle>`, r, r, r, verdictColor(v), title)
+			break
+		}
+		next := angle + 2*math.Pi*float64(n)/float64(len(tests))
+		large := 0
+		if next-angle > math.Pi {
+			large = 1
+		}
+		fmt.Fprintf(&b, `<path d="M%g,%g L%.2f,%.2f A%g,%g 0 %d,1 %.2f,%.2f Z" fill="%s">%s</path>`,
+			r, r, r+r*math.Sin(angle), r-r*math.Cos(angle), r, r, large, r+r*math.Sin(next), r-r*math.Cos(next), verdictColor(v), title)
+		angle = next
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// durationHistogram returns an SVG histogram of test durations.
+func durationHistogram(tests RunSlice) template.HTML {
+	const (
+		bins          = 12
+		width, height = 480, 160
+		bar           = width / bins
+	)
+	if len(tests) == 0 {
+		return ""
+	}
+	max := tests.Longest().Duration()
+	if max <= 0 {
+		max = time.Second
+	}
+	var counts [bins]int
+	for _, t := range tests {
+		i := int(float64(t.Duration()) / float64(max) * bins)
+		if i >= bins {
+			i = bins - 1
+		}
+		counts[i]++
+	}
+	highest := 1
+	for _, n := range counts {
+		if n > highest {
+			highest = n
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" width="%d" height="%d" viewBox="0 0 %d %d">`, width, height+20, width, height+20)
+	for i, n := range counts {
+		h := float64(height) * float64(n) / float64(highest)
+		lo := time.Duration(float64(max) * float64(i) / bins).Round(time.Millisecond)
+		hi := time.Duration(float64(max) * float64(i+1) / bins).Round(time.Millisecond)
+		fmt.Fprintf(&b, `<rect x="%d" y="%.1f" width="%d" height="%.1f" fill="#4c6ef5"><title>%s – %s: %d tests</title></rect>`,
+			i*bar+1, float64(height)-h, bar-2, h, lo, hi, n)
+	}
+	fmt.Fprintf(&b, `<text x="0" y="%d">0s</text><text x="%d" y="%d" text-anchor="end">%s</text>`, height+15, width, height+15, max.Round(time.Millisecond))
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// loadTimeline returns an SVG line chart of the system load at the begin of
+// each run.
+func loadTimeline(runs RunSlice) template.HTML {
+	const width, height = 480, 160
+	var points []Run
+	for _, r := range runs {
+		if r.Load > 0 && !r.Begin.IsZero() {
+			points = append(points, r)
+		}
+	}
+	if len(points) == 0 {
+		return ""
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Begin.Before(points[j].Begin.Time) })
+
+	first, last := points[0].Begin.Time, points[len(points)-1].Begin.Time
+	span := last.Sub(first)
+	if span <= 0 {
+		span = time.Second
+	}
+	maxLoad := 1.0
+	for _, p := range points {
+		maxLoad = math.Max(maxLoad, p.Load)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="chart" width="%d" height="%d" viewBox="0 0 %d %d"><polyline fill="none" stroke="#f08c00" stroke-width="2" points="`, width, height+20, width, height+20)
+	for _, p := range points {
+		x := float64(width) * float64(p.Begin.Sub(first)) / float64(span)
+		y := float64(height) - float64(height)*p.Load/maxLoad
+		fmt.Fprintf(&b, "%.1f,%.1f ", x, y)
+	}
+	fmt.Fprintf(&b, `"/><text x="0" y="%d">%s</text><text x="%d" y="%d" text-anchor="end">%s (max load %.2f)</text></svg>`,
+		height+15, first.Format("15:04:05"), width, height+15, last.Format("15:04:05"), maxLoad)
+	return template.HTML(b.String())
+}
+
+const HTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}} – test report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #212529; }
+h1 { margin-bottom: 0; }
+.meta { color: #868e96; }
+.overview { display: flex; flex-wrap: wrap; gap: 2em; align-items: flex-start; }
+.overview > div { min-width: 200px; }
+.chart text { font-size: 11px; fill: #868e96; }
+table { border-collapse: collapse; width: 100%; }
+td, th { text-align: left; padding: 0.2em 0.6em; border-bottom: 1px solid #dee2e6; vertical-align: top; }
+details.module { margin: 0.5em 0; border: 1px solid #dee2e6; border-radius: 4px; padding: 0.5em; }
+details.module > summary { cursor: pointer; display: flex; align-items: center; gap: 1em; }
+.verdict { font-weight: bold; }
+pre { background: #f8f9fa; padding: 0.5em; overflow: auto; max-height: 20em; }
+.PASSED { color: #2e9e44; } .FAILED { color: #e03131; } .UNSTABLE { color: #f08c00; } .NORUN { color: #868e96; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<p class="meta">Generated {{.Generated.Format "2006-01-02 15:04:05"}} · {{len .Tests}} tests · {{len .Runs}} runs · {{.Cores}} CPU cores · parallel tests {{.MaxJobs}} · load limit {{.MaxLoad}}</p>
+<h2 class="{{.Tests.Result}}">{{.Tests.Result}}</h2>
+
+<div class="overview">
+<div>
+<h3>Verdicts</h3>
+{{.Pie}}
+<table>
+<tr><td>failed</td><td>{{len .Tests.Failed}}</td></tr>
+<tr><td>unstable</td><td>{{len .Tests.Unstable}}</td></tr>
+<tr><td>quarantined</td><td>{{len .Tests.Quarantined}}</td></tr>
+<tr><td>skipped</td><td>{{len .Tests.Skipped}}</td></tr>
+</table>
+</div>
+<div>
+<h3>Durations</h3>
+{{with .Histogram}}{{.}}{{else}}<p>no data</p>{{end}}
+<table>
+<tr><td>average</td><td>{{.Tests.Average}} (±{{.Tests.Deviation}})</td></tr>
+<tr><td>shortest</td><td>{{.Tests.Shortest.Duration}}</td></tr>
+<tr><td>longest</td><td>{{.Tests.Longest.Duration}} ({{.Tests.Longest.Name}})</td></tr>
+<tr><td>total</td><td>{{.Tests.Total}} in {{.Tests.Duration}}</td></tr>
+</table>
+</div>
+<div>
+<h3>System load</h3>
+{{with .Timeline}}{{.}}{{else}}<p>no data</p>{{end}}
+</div>
+</div>
+
+<h2>Modules</h2>
+{{range .Modules}}
+<details class="module"{{if .Tests.Failed}} open{{end}}>
+<summary>{{.Pie}} <b>{{.Name}}</b> <span class="{{.Tests.Result}}">{{.Tests.Result}}</span> <span class="meta">{{len .Tests}} tests, {{len .Tests.Failed}} failed, {{seconds .Tests.Total}}</span></summary>
+<table>
+<tr><th>Verdict</th><th>Test</th><th>Duration</th><th>Load</th><th>Source</th></tr>
+{{range .Rows}}
+<tr>
+<td class="verdict" style="color: {{verdictColor .Verdict}}">{{.Verdict}}</td>
+<td>{{.Testcase}}{{with .Reason}}<br><span class="meta">{{.}}</span>{{end}}
+{{range .Reasons}}<details><summary>{{.Name}}</summary><pre>{{.Content}}</pre></details>{{end}}</td>
+<td>{{seconds .Duration}}</td>
+<td>{{printf "%.2f" .Load}}</td>
+<td>{{if .URL}}<a href="{{.URL}}">{{.Location}}</a>{{end}}</td>
+</tr>
+{{end}}
+</table>
+</details>
+{{end}}
+</body>
+</html>
+`
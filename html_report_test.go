@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/nokia/ntt/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHTMLReport(t *testing.T) {
+	Project = &project.Config{ResultsFile: "testdata/html_report/test_results.json"}
+	defer func() { Project = nil }()
+
+	var buf bytes.Buffer
+	if err := writeHTMLReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	// Tokenize the report with the HTML settings of the XML decoder
+	// and count the module sections.
+	d := xml.NewDecoder(strings.NewReader(out))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	modules := 0
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("report is not valid HTML: %s", err.Error())
+		}
+		if e, ok := tok.(xml.StartElement); ok && e.Name.Local == "details" {
+			for _, a := range e.Attr {
+				if a.Name.Local == "class" && a.Value == "module" {
+					modules++
+				}
+			}
+		}
+	}
+	assert.Equal(t, 2, modules)
+	assert.Contains(t, out, "<b>A</b>")
+	assert.Contains(t, out, "<b>B</b>")
+
+	assert.Contains(t, out, "testdata/html_report/A.TC2/verdict.reason")
+	assert.Contains(t, out, "received &lt;ack&gt; instead of &lt;nack&gt;")
+	assert.Contains(t, out, "&lt;i&gt;x&lt;/i&gt;")
+	assert.NotContains(t, out, "<i>x</i>")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/results"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ImportResultsCommand = &cobra.Command{
+		Use:   "import-results FILE...",
+		Short: "Import test results of other test executors",
+		Long: `Import test results of other test executors.
+
+The import-results command converts the output of other test executors into
+the results file of the project (test_results.json by default), which is used
+by ntt report and ntt flaky. Every FILE becomes a session. Supported formats are:
+
+  junit   JUnit XML reports
+  tap     Test Anything Protocol streams
+  titan   Titan executor logs (test case verdicts)
+
+The format is detected by file extension and content, unless --format is
+given. Imported sessions are appended to the existing sessions of the results
+file. Files imported before are skipped. Use --replace to discard the existing
+sessions.
+`,
+		Args:        cobra.MinimumNArgs(1),
+		Annotations: map[string]string{projectArgs: projectArgsNone},
+		RunE:        importResults,
+	}
+
+	importFormat  string
+	importReplace bool
+)
+
+func init() {
+	ImportResultsCommand.Flags().StringVar(&importFormat, "format", "", "format of input files (junit, tap or titan)")
+	ImportResultsCommand.Flags().BoolVar(&importReplace, "replace", false, "replace existing sessions")
+}
+
+func importResults(cmd *cobra.Command, args []string) error {
+	db := &results.DB{}
+	if !importReplace {
+		latest, err := latestResults()
+		if err != nil {
+			return err
+		}
+		db = latest
+	}
+
+	// Session IDs of imports are derived from the file content. Skip
+	// files, which have been imported already.
+	seen := make(map[string]bool)
+	for _, s := range db.Sessions {
+		seen[s.Id] = true
+	}
+
+	runs := 0
+	for _, file := range args {
+		s, err := results.ImportFile(file, importFormat)
+		if err != nil {
+			return err
+		}
+		if seen[s.Id] {
+			log.Verbosef("%s: already imported as session %s\n", file, s.Id)
+			continue
+		}
+		seen[s.Id] = true
+		log.Verbosef("%s: imported %d runs\n", file, len(s.Runs))
+		db.Sessions = append(db.Sessions, *s)
+		runs += len(s.Runs)
+	}
+
+	file := resultsFile()
+	if err := db.WriteFile(file); err != nil {
+		return err
+	}
+	if !outputQuiet {
+		fmt.Printf("imported %d test runs into %s\n", runs, file)
+	}
+	return nil
+}
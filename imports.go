@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/imports"
+	"github.com/spf13/cobra"
+)
+
+var ImportsCommand = &cobra.Command{
+	Use:   "imports",
+	Short: "Organize import statements",
+	Long: `Organize import statements.
+
+The imports command organizes the import statements of the given source files:
+
+  * imports of the same module are merged and sorted by module name.
+  * "import from X all" is replaced by an explicit list of the definitions
+    actually used.
+  * imports which do not provide any used definition are removed.
+
+Imports using except-clauses, group- or import-imports, language
+specifications, with-attributes or visibility modifiers are kept as they are.
+
+Like the format command, the result is written to stdout by default. Use
+--in-place, --diff or --list to change this behaviour.
+`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		srcs, err := fs.TTCN3Files(Project.Sources...)
+		if err != nil {
+			return err
+		}
+		files, err := project.Files(Project)
+		if err != nil {
+			return err
+		}
+
+		var db ttcn3.DB
+		db.Index(files...)
+
+		var merr *multierror.Error
+		for _, src := range srcs {
+			if err := organizeImports(src, &db); err != nil {
+				merr = multierror.Append(merr, err)
+			}
+		}
+
+		if formattedFiles > 0 && (listFiles || diff) {
+			return fmt.Errorf("%d files with unorganized imports", formattedFiles)
+		}
+
+		return merr.ErrorOrNil()
+	},
+}
+
+func init() {
+	ImportsCommand.Flags().BoolVarP(&inplace, "in-place", "i", false, "organize files in place")
+	ImportsCommand.Flags().BoolVarP(&diff, "diff", "d", false, "display diff instead of rewriting files. Exit with non-zero status if any imports need to be organized")
+	ImportsCommand.Flags().BoolVarP(&listFiles, "list", "l", false, "list files with unorganized imports. Exit with non-zero status if any imports need to be organized")
+}
+
+func organizeImports(path string, db *ttcn3.DB) error {
+	src, err := fs.Content(path)
+	if err != nil {
+		return err
+	}
+	edits, err := imports.Organize(ttcn3.ParseFile(path), db)
+	if err != nil {
+		return err
+	}
+	return writeResult(path, src, imports.Apply(src, edits))
+}
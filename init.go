@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/project"
+	"github.com/spf13/cobra"
+)
+
+var (
+	InitCommand = &cobra.Command{
+		Use:   "init [DIR]",
+		Short: "Create a new test suite",
+		Long: `Create a new test suite.
+
+The init command creates a test suite in DIR (default: current directory):
+
+  package.yml         manifest with name, sources and imports
+  NAME.ttcn3          example module with a testcase and a control part
+  NAME.parameters     parameters file with a preset
+  ntt-lint.yml        lint configuration
+  CMakeLists.txt      CMake snippet using FindNTT.cmake (with --cmake)
+
+NAME defaults to the name of DIR. NAME is also the identifier of the example
+module and must be a valid TTCN-3 identifier. Existing files are not
+overwritten, unless --force is given.
+`,
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{projectArgs: projectArgsSkip},
+		RunE:        initSuite,
+	}
+
+	initName  string
+	initCMake bool
+	initForce bool
+)
+
+func init() {
+	InitCommand.Flags().StringVar(&initName, "name", "", "name of the test suite")
+	InitCommand.Flags().BoolVar(&initCMake, "cmake", false, "generate a CMakeLists.txt")
+	InitCommand.Flags().BoolVarP(&initForce, "force", "f", false, "overwrite existing files")
+}
+
+func initSuite(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	name := initName
+	if name == "" {
+		n, err := project.NameFromURI(dir)
+		if err != nil {
+			return err
+		}
+		name = n
+	}
+
+	files, err := project.Scaffold(name, initCMake)
+	if err != nil && initName == "" {
+		return fmt.Errorf("%w: use --name to choose a suite name", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for file := range files {
+		names = append(names, file)
+		if path := filepath.Join(dir, file); !initForce && fs.IsRegular(path) {
+			return fmt.Errorf("%s exists already. Use --force to overwrite", path)
+		}
+	}
+	sort.Strings(names)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, file := range names {
+		path := filepath.Join(dir, file)
+		if err := os.WriteFile(path, files[file], 0644); err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
@@ -151,7 +151,7 @@ func ParseFiles(files ...string) Env {
 	}
 
 	var env Env
-	for _, path := range Files {
+	for _, path := range files {
 		f := fs.Open(cache.Lookup(path))
 		b, err := f.Bytes()
 		if err != nil {
@@ -0,0 +1,74 @@
+package lsp
+
+import (
+	"context"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/lsp/protocol"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/imports"
+	"github.com/nokia/ntt/ttcn3/syntax"
+)
+
+func (s *Server) codeAction(ctx context.Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
+	if !wantCodeAction(params.Context.Only, protocol.SourceOrganizeImports) {
+		return nil, nil
+	}
+
+	uri := string(params.TextDocument.URI)
+	src, err := fs.Content(uri)
+	if err != nil {
+		log.Debug("organize imports: ", err.Error())
+		return nil, nil
+	}
+	tree := ttcn3.ParseFile(uri)
+	edits, err := imports.Organize(tree, &s.db)
+	if err != nil {
+		log.Debug("organize imports: ", err.Error())
+		return nil, nil
+	}
+	if len(edits) == 0 {
+		return nil, nil
+	}
+
+	textEdits := make([]protocol.TextEdit, 0, len(edits))
+	for _, e := range edits {
+		textEdits = append(textEdits, protocol.TextEdit{
+			Range:   setProtocolRange(offsetPosition(tree, src, e.Pos), offsetPosition(tree, src, e.End)),
+			NewText: e.Text,
+		})
+	}
+
+	return []protocol.CodeAction{{
+		Title: "Organize imports",
+		Kind:  protocol.SourceOrganizeImports,
+		Edit: protocol.WorkspaceEdit{
+			Changes: map[string][]protocol.TextEdit{uri: textEdits},
+		},
+	}}, nil
+}
+
+// wantCodeAction returns true if the client requested code actions of the
+// given kind. An empty list requests all kinds.
+func wantCodeAction(only []protocol.CodeActionKind, kind protocol.CodeActionKind) bool {
+	if len(only) == 0 {
+		return true
+	}
+	for _, k := range only {
+		if k == kind || k == protocol.Source {
+			return true
+		}
+	}
+	return false
+}
+
+// offsetPosition returns the position of offset. Unlike Root.Position it
+// also handles the end of files with a trailing newline.
+func offsetPosition(tree *ttcn3.Tree, src []byte, offset int) syntax.Position {
+	if offset > 0 && offset == len(src) && src[offset-1] == '\n' {
+		pos := tree.Position(offset - 1)
+		return syntax.Position{Line: pos.Line + 1, Column: 1}
+	}
+	return tree.Position(offset)
+}
@@ -106,7 +106,7 @@ func (s *Server) initialize(ctx context.Context, params *protocol.ParamInitializ
 	return &protocol.InitializeResult{
 		Capabilities: protocol.ServerCapabilities{
 			InlayHintProvider:               s.registerInlayHintIfNoDynReg(),
-			CodeActionProvider:              false,
+			CodeActionProvider:              protocol.CodeActionOptions{CodeActionKinds: []protocol.CodeActionKind{protocol.SourceOrganizeImports}},
 			CompletionProvider:              protocol.CompletionOptions{TriggerCharacters: []string{"."}},
 			DefinitionProvider:              true,
 			TypeDefinitionProvider:          false,
@@ -16,8 +16,8 @@ func (s *Server) InlayHint(ctx context.Context, params *protocol.InlayHintParams
 	return s.inlayHint(ctx, params)
 }
 
-func (s *Server) CodeAction(context.Context, *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
-	return nil, notImplemented("CodeAction")
+func (s *Server) CodeAction(ctx context.Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
+	return s.codeAction(ctx, params)
 }
 
 func (s *Server) CodeLens(ctx context.Context, params *protocol.CodeLensParams) ([]protocol.CodeLens, error) {
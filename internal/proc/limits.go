@@ -0,0 +1,44 @@
+package proc
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrNotSupported is returned by functions not supported on the current
+// platform.
+var ErrNotSupported = errors.New("not supported on this platform")
+
+// LoadAvgFile is the file providing the system load.
+var LoadAvgFile = "/proc/loadavg"
+
+// LoadAvg returns the system load average of the last minute.
+func LoadAvg() (float64, error) {
+	b, err := os.ReadFile(LoadAvgFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, ErrNotSupported
+		}
+		return 0, err
+	}
+	return parseLoadAvg(string(b))
+}
+
+func parseLoadAvg(s string) (float64, error) {
+	f := strings.Fields(s)
+	if len(f) == 0 {
+		return 0, errors.New("loadavg: unexpected format")
+	}
+	return strconv.ParseFloat(f[0], 64)
+}
+
+// MaxRSS returns the maximum resident set size in bytes of an exited process
+// or 0 if not available.
+func MaxRSS(state *os.ProcessState) int {
+	if state == nil {
+		return 0
+	}
+	return maxRSS(state)
+}
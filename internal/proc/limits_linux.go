@@ -0,0 +1,23 @@
+package proc
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// SetMemoryLimit limits the virtual memory of the process with the given pid
+// and its future children to the given number of bytes. Allocations
+// exceeding the limit fail.
+func SetMemoryLimit(pid int, bytes uint64) error {
+	return unix.Prlimit(pid, unix.RLIMIT_AS, &unix.Rlimit{Cur: bytes, Max: bytes}, nil)
+}
+
+func maxRSS(state *os.ProcessState) int {
+	if ru, ok := state.SysUsage().(*syscall.Rusage); ok {
+		// Linux reports kilobytes.
+		return int(ru.Maxrss) * 1024
+	}
+	return 0
+}
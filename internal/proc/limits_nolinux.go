@@ -0,0 +1,17 @@
+//go:build !linux
+// +build !linux
+
+package proc
+
+import "os"
+
+// SetMemoryLimit limits the virtual memory of the process with the given pid
+// and its future children to the given number of bytes. Allocations
+// exceeding the limit fail.
+func SetMemoryLimit(pid int, bytes uint64) error {
+	return ErrNotSupported
+}
+
+func maxRSS(state *os.ProcessState) int {
+	return 0
+}
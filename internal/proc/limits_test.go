@@ -0,0 +1,30 @@
+package proc
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLoadAvg(t *testing.T) {
+	load, err := parseLoadAvg("0.52 0.58 0.59 1/467 12345\n")
+	assert.Nil(t, err)
+	assert.Equal(t, 0.52, load)
+
+	_, err = parseLoadAvg("")
+	assert.NotNil(t, err)
+}
+
+func TestMemoryLimit(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("memory limits are only supported on linux")
+	}
+	cmd := Command("sleep", "0.1")
+	if err := cmd.Start(); err != nil {
+		t.Skip(err)
+	}
+	assert.Nil(t, SetMemoryLimit(cmd.Process.Pid, 1<<30))
+	assert.Nil(t, cmd.Wait())
+	assert.Greater(t, MaxRSS(cmd.ProcessState), 0)
+}
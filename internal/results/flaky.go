@@ -0,0 +1,76 @@
+package results
+
+import "sort"
+
+// Flakiness describes how flaky a test behaved across multiple sessions.
+type Flakiness struct {
+	// Name is the full qualified test name.
+	Name string `json:"name"`
+
+	// Score is the fraction of sessions, in which the test behaved
+	// flaky: either its verdict was unstable within the session or it
+	// changed between passing and not passing compared to the previous
+	// session.
+	Score float64 `json:"score"`
+
+	// Sessions is the number of sessions the test was executed in.
+	Sessions int `json:"sessions"`
+
+	// Unstable is the number of sessions with an unstable verdict.
+	Unstable int `json:"unstable"`
+
+	// Flips is the number of changes between passing and not passing
+	// between consecutive sessions.
+	Flips int `json:"flips"`
+}
+
+// FlakinessScores computes the flakiness of all tests of the given sessions,
+// which are expected in chronological order. The result is sorted by score,
+// highest first, and name.
+func FlakinessScores(sessions ...Session) []Flakiness {
+	var (
+		scores = make(map[string]*Flakiness)
+		flaky  = make(map[string]int)
+		last   = make(map[string]bool)
+	)
+	for _, s := range sessions {
+		for _, r := range FinalVerdicts(s.Runs) {
+			if r.Verdict == "skipped" {
+				continue
+			}
+			f, ok := scores[r.Name]
+			if !ok {
+				f = &Flakiness{Name: r.Name}
+				scores[r.Name] = f
+			}
+			f.Sessions++
+
+			passed := r.Verdict == "pass" || r.Verdict == "unstable"
+			switch {
+			case r.Verdict == "unstable":
+				f.Unstable++
+				flaky[r.Name]++
+				if ok && !last[r.Name] {
+					f.Flips++
+				}
+			case ok && passed != last[r.Name]:
+				f.Flips++
+				flaky[r.Name]++
+			}
+			last[r.Name] = passed
+		}
+	}
+
+	list := make([]Flakiness, 0, len(scores))
+	for name, f := range scores {
+		f.Score = float64(flaky[name]) / float64(f.Sessions)
+		list = append(list, *f)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
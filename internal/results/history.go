@@ -0,0 +1,272 @@
+package results
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File name of the test history file
+var HistoryFilename = "test_history.jsonl"
+
+// An Entry is a test session recorded in the history.
+type Entry struct {
+	Session
+
+	// Commit is the version control revision the session tested.
+	Commit string `json:"commit,omitempty"`
+
+	// Timestamp is when the session started.
+	Timestamp Timestamp `json:"timestamp"`
+}
+
+// A History is an append-only store of test sessions. Each session is stored
+// as a single JSON object per line.
+type History struct {
+	Path string
+}
+
+// OpenHistory returns the history stored in the given file. The file is
+// created when the first entry is appended.
+func OpenHistory(path string) *History {
+	return &History{Path: path}
+}
+
+// Entries returns all entries of the history in the order they were
+// appended.
+func (h *History) Entries() ([]Entry, error) {
+	f, err := os.Open(h.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var list []Entry
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; s.Scan(); line++ {
+		if strings.TrimSpace(s.Text()) == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", h.Path, line, err)
+		}
+		list = append(list, e)
+	}
+	return list, s.Err()
+}
+
+// Append appends entries to the history. Entries with a session ID already
+// recorded are skipped.
+func (h *History) Append(entries ...Entry) error {
+	existing, err := h.Entries()
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, e := range existing {
+		seen[e.Id] = true
+	}
+
+	f, err := os.OpenFile(h.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Id != "" && seen[e.Id] {
+			continue
+		}
+		seen[e.Id] = true
+		b, err := json.Marshal(e)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := f.Write(append(b, '\n')); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// Since returns the entries with a timestamp not before t.
+func (h *History) Since(t time.Time) ([]Entry, error) {
+	entries, err := h.Entries()
+	if err != nil {
+		return nil, err
+	}
+	var list []Entry
+	for _, e := range entries {
+		if !e.Timestamp.Before(t) {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+// Lookup returns the most recent entry with the given session ID or with a
+// commit starting with ref.
+func (h *History) Lookup(ref string) (Entry, error) {
+	entries, err := h.Entries()
+	if err != nil {
+		return Entry{}, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if e := entries[i]; e.Id == ref || (e.Commit != "" && strings.HasPrefix(e.Commit, ref)) {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("session %q not found in %s", ref, h.Path)
+}
+
+// NewEntry returns a history entry for session s. The timestamp is the begin
+// of the first run of the session.
+func NewEntry(s Session, commit string) Entry {
+	return Entry{
+		Session:   s,
+		Commit:    commit,
+		Timestamp: First(s.Runs).Begin,
+	}
+}
+
+// EntryRuns returns the runs of all entries.
+func EntryRuns(entries ...Entry) []Run {
+	db := DB{}
+	for _, e := range entries {
+		db.Sessions = append(db.Sessions, e.Session)
+	}
+	return db.Runs()
+}
+
+// ParseSince parses a point in time relative to now. Supported are durations
+// (e.g. "36h"), days (e.g. "7d"), dates (e.g. "2006-01-02") and RFC 3339
+// timestamps.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if n, err := strconv.Atoi(strings.TrimSuffix(s, "d")); err == nil && strings.HasSuffix(s, "d") {
+		return now.AddDate(0, 0, -n), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a duration (36h, 7d), a date (2006-01-02) or RFC 3339", s)
+}
+
+// Thresholds for reporting duration regressions: A test regressed if its
+// average duration grew by more than RegressionFactor and at least
+// RegressionMin.
+var (
+	RegressionFactor = 1.2
+	RegressionMin    = time.Second
+)
+
+// A Comparison describes the changes of test results between two sets of
+// runs.
+type Comparison struct {
+	NewlyFailing []Run        `json:"newly_failing"`
+	NewlyPassing []Run        `json:"newly_passing"`
+	NewlyFlaky   []Run        `json:"newly_flaky"`
+	Regressions  []Regression `json:"regressions"`
+}
+
+// A Regression describes a test whose average duration increased.
+type Regression struct {
+	Name   string        `json:"name"`
+	Before time.Duration `json:"before"`
+	After  time.Duration `json:"after"`
+}
+
+// Compare compares the final verdicts and average durations of the tests
+// in before and after. Only tests present in both are compared. The result
+// is sorted by test name.
+func Compare(before, after []Run) Comparison {
+	prev := make(map[string]Run)
+	for _, r := range FinalVerdicts(before) {
+		prev[r.JobID()] = r
+	}
+
+	cmp := Comparison{
+		NewlyFailing: []Run{},
+		NewlyPassing: []Run{},
+		NewlyFlaky:   []Run{},
+		Regressions:  []Regression{},
+	}
+	for _, r := range FinalVerdicts(after) {
+		p, ok := prev[r.JobID()]
+		if !ok {
+			continue
+		}
+		switch {
+		case failed(r.Verdict) && !failed(p.Verdict):
+			cmp.NewlyFailing = append(cmp.NewlyFailing, r)
+		case r.Verdict == "pass" && failed(p.Verdict):
+			cmp.NewlyPassing = append(cmp.NewlyPassing, r)
+		case r.Verdict == "unstable" && p.Verdict != "unstable":
+			cmp.NewlyFlaky = append(cmp.NewlyFlaky, r)
+		}
+	}
+
+	b, a := durationsByName(before), durationsByName(after)
+	for name, d := range a {
+		if _, ok := b[name]; !ok {
+			continue
+		}
+		x, y := Average(b[name]), Average(d)
+		if float64(y) > float64(x)*RegressionFactor && y-x >= RegressionMin {
+			cmp.Regressions = append(cmp.Regressions, Regression{Name: name, Before: x, After: y})
+		}
+	}
+
+	byName := func(runs []Run) {
+		sort.Slice(runs, func(i, j int) bool { return runs[i].Name < runs[j].Name })
+	}
+	byName(cmp.NewlyFailing)
+	byName(cmp.NewlyPassing)
+	byName(cmp.NewlyFlaky)
+	sort.Slice(cmp.Regressions, func(i, j int) bool { return cmp.Regressions[i].Name < cmp.Regressions[j].Name })
+	return cmp
+}
+
+// failed returns true if the verdict is neither pass, unstable nor skipped.
+func failed(verdict string) bool {
+	switch verdict {
+	case "pass", "unstable", "skipped":
+		return false
+	}
+	return true
+}
+
+func durationsByName(runs []Run) map[string][]time.Duration {
+	m := make(map[string][]time.Duration)
+	for _, r := range runs {
+		if r.Verdict != "skipped" {
+			m[r.Name] = append(m[r.Name], r.Duration())
+		}
+	}
+	return m
+}
+
+// ExpectedDurations returns the expected duration of every test, which is the
+// median of the durations of its runs. Skipped runs are ignored.
+func ExpectedDurations(runs []Run) map[string]time.Duration {
+	ret := make(map[string]time.Duration)
+	for name, ds := range durationsByName(runs) {
+		ret[name] = Average(ds)
+	}
+	return ret
+}
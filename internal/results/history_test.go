@@ -0,0 +1,111 @@
+package results
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func timed(verdict, id string, begin time.Time, d time.Duration) Run {
+	r := run(verdict, id)
+	r.Begin = Timestamp{begin}
+	r.End = Timestamp{begin.Add(d)}
+	return r
+}
+
+func TestHistory(t *testing.T) {
+	h := OpenHistory(filepath.Join(t.TempDir(), HistoryFilename))
+
+	entries, err := h.Entries()
+	assert.Nil(t, err)
+	assert.Nil(t, entries)
+
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s1 := Session{Id: "s1", Runs: []Run{timed("pass", "A-0", day, time.Second)}}
+	s2 := Session{Id: "s2", Runs: []Run{timed("fail", "A-0", day.AddDate(0, 0, 1), time.Second)}}
+
+	assert.Nil(t, h.Append(NewEntry(s1, "abcdef")))
+	assert.Nil(t, h.Append(NewEntry(s1, "abcdef"), NewEntry(s2, "123456")))
+
+	entries, err = h.Entries()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "s1", entries[0].Id)
+	assert.Equal(t, day.Unix(), entries[0].Timestamp.Unix())
+	assert.Equal(t, "s2", entries[1].Id)
+
+	e, err := h.Lookup("123")
+	assert.Nil(t, err)
+	assert.Equal(t, "s2", e.Id)
+	_, err = h.Lookup("s3")
+	assert.NotNil(t, err)
+
+	entries, err = h.Since(day.Add(time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "s2", entries[0].Id)
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		Input string
+		Want  time.Time
+	}{
+		{"36h", now.Add(-36 * time.Hour)},
+		{"7d", time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC)},
+		{"2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.Input, now)
+		assert.Nil(t, err)
+		assert.True(t, tt.Want.Equal(got), tt.Input)
+	}
+
+	_, err := ParseSince("yesterday", now)
+	assert.NotNil(t, err)
+}
+
+func TestCompare(t *testing.T) {
+	t0 := time.Now()
+	before := []Run{
+		timed("pass", "A-0", t0, time.Second),
+		timed("fail", "B-0", t0, time.Second),
+		timed("pass", "C-0", t0, time.Second),
+		timed("pass", "D-0", t0, 2*time.Second),
+		timed("pass", "E-0", t0, 10*time.Second),
+	}
+	after := []Run{
+		timed("fail", "A-0", t0, time.Second),
+		timed("pass", "B-0", t0, time.Second),
+		timed("fail", "C-0", t0, time.Second),
+		timed("pass", "C-1", t0, time.Second),
+		timed("pass", "D-0", t0, 4*time.Second),
+		timed("pass", "E-0", t0, 11*time.Second),
+		timed("fail", "F-0", t0, time.Second),
+	}
+
+	cmp := Compare(before, after)
+	assert.Equal(t, []string{"fail	A-0	1s"}, stringSlice(cmp.NewlyFailing))
+	assert.Equal(t, []string{"pass	B-0	1s"}, stringSlice(cmp.NewlyPassing))
+	assert.Equal(t, []string{"unstable	C-0	1s"}, stringSlice(cmp.NewlyFlaky))
+	assert.Equal(t, []Regression{{Name: "D", Before: 2 * time.Second, After: 4 * time.Second}}, cmp.Regressions)
+}
+
+func TestExpectedDurations(t *testing.T) {
+	now := time.Now()
+	runs := []Run{
+		timed("pass", "A-0", now, 1*time.Second),
+		timed("fail", "A-1", now, 3*time.Second),
+		timed("pass", "A-2", now, 100*time.Second),
+		timed("pass", "B-0", now, 2*time.Second),
+		timed("skipped", "C-0", now, 5*time.Second),
+	}
+	assert.Equal(t, map[string]time.Duration{
+		"A": 3 * time.Second,
+		"B": 2 * time.Second,
+	}, ExpectedDurations(runs))
+}
@@ -0,0 +1,305 @@
+package results
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An Importer converts the output of a test executor into a session. Base is
+// used as begin of the session, when the output does not provide timestamps.
+type Importer func(b []byte, base time.Time) (*Session, error)
+
+// Importers maps format names to importers.
+var Importers = map[string]Importer{
+	"junit": ImportJUnit,
+	"tap":   ImportTAP,
+	"titan": ImportTitan,
+}
+
+// DetectFormat returns the format of a test executor output by looking at the
+// file name and content.
+func DetectFormat(name string, b []byte) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".xml":
+		return "junit", nil
+	case ".tap":
+		return "tap", nil
+	}
+
+	s := strings.TrimSpace(string(b))
+	switch {
+	case strings.HasPrefix(s, "<"):
+		return "junit", nil
+	case strings.HasPrefix(s, "TAP version"), tapPlan.MatchString(firstLine(s)), tapResult.MatchString(firstLine(s)):
+		return "tap", nil
+	case titanStarted.MatchString(s):
+		return "titan", nil
+	}
+	return "", fmt.Errorf("%s: unknown test output format", name)
+}
+
+// ImportFile imports a test executor output file. If format is empty, it is
+// detected automatically. The modification time of the file is used as
+// begin of the session, if the file does not provide timestamps.
+func ImportFile(path string, format string) (*Session, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		if format, err = DetectFormat(path, b); err != nil {
+			return nil, err
+		}
+	}
+	imp, ok := Importers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	var base time.Time
+	if fi, err := os.Stat(path); err == nil {
+		base = fi.ModTime()
+	}
+	s, err := imp(b, base)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// WriteFile writes the database to a file in JSON format. Missing parent
+// directories are created.
+func (db *DB) WriteFile(path string) error {
+	b, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// sessionID returns a session ID derived from the imported content. Importing
+// the same content twice results in the same session ID.
+func sessionID(b []byte) string {
+	sum := sha256.Sum256(b)
+	return "import-" + hex.EncodeToString(sum[:8])
+}
+
+type junitSuites struct {
+	Suites []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string       `xml:"name,attr"`
+	Timestamp string       `xml:"timestamp,attr"`
+	Cases     []junitCase  `xml:"testcase"`
+	Suites    []junitSuite `xml:"testsuite"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (m *junitMessage) reason() string {
+	if s := strings.TrimSpace(m.Message); s != "" {
+		return s
+	}
+	return strings.TrimSpace(m.Text)
+}
+
+// ImportJUnit imports JUnit XML reports. Test names are qualified by the
+// class name of the testcase or, if it has none, by the name of the test
+// suite. Failures are imported as verdict fail, errors as error.
+func ImportJUnit(b []byte, base time.Time) (*Session, error) {
+	var root junitSuites
+	if bytes.Contains(b, []byte("<testsuites")) {
+		if err := xml.Unmarshal(b, &root); err != nil {
+			return nil, err
+		}
+	} else {
+		var s junitSuite
+		if err := xml.Unmarshal(b, &s); err != nil {
+			return nil, err
+		}
+		root.Suites = []junitSuite{s}
+	}
+
+	s := &Session{Id: sessionID(b)}
+	var walk func(suites []junitSuite)
+	walk = func(suites []junitSuite) {
+		for _, suite := range suites {
+			begin := base
+			if t, err := time.ParseInLocation("2006-01-02T15:04:05", suite.Timestamp, time.Local); err == nil {
+				begin = t
+			} else if t, err := time.Parse(time.RFC3339, suite.Timestamp); err == nil {
+				begin = t
+			}
+			for _, tc := range suite.Cases {
+				r := Run{Name: tc.Name, Verdict: "pass"}
+				if prefix := tc.ClassName; prefix != "" {
+					r.Name = prefix + "." + tc.Name
+				} else if suite.Name != "" {
+					r.Name = suite.Name + "." + tc.Name
+				}
+				switch {
+				case tc.Error != nil:
+					r.Verdict, r.Reason = "error", tc.Error.reason()
+				case tc.Failure != nil:
+					r.Verdict, r.Reason = "fail", tc.Failure.reason()
+				case tc.Skipped != nil:
+					r.Verdict, r.Reason = "skipped", tc.Skipped.reason()
+				}
+				secs, _ := strconv.ParseFloat(tc.Time, 64)
+				r.Begin = Timestamp{begin}
+				begin = begin.Add(time.Duration(secs * float64(time.Second)))
+				r.End = Timestamp{begin}
+				s.Runs = append(s.Runs, r)
+			}
+			walk(suite.Suites)
+		}
+	}
+	walk(root.Suites)
+	return s, nil
+}
+
+var (
+	tapPlan     = regexp.MustCompile(`^1\.\.\d+`)
+	tapResult   = regexp.MustCompile(`^(not )?ok\b\s*(\d+)?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(\w+)\s*(.*))?$`)
+	tapDuration = regexp.MustCompile(`^\s+duration_ms:\s*([0-9.]+)`)
+)
+
+// ImportTAP imports Test Anything Protocol streams. Tests with directive SKIP
+// or TODO are imported as skipped. Durations are taken from the duration_ms
+// field of YAML diagnostic blocks, if present.
+func ImportTAP(b []byte, base time.Time) (*Session, error) {
+	s := &Session{Id: sessionID(b)}
+	begin := base
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for sc.Scan() {
+		line := sc.Text()
+		if m := tapDuration.FindStringSubmatch(line); m != nil && len(s.Runs) > 0 {
+			ms, _ := strconv.ParseFloat(m[1], 64)
+			r := &s.Runs[len(s.Runs)-1]
+			r.End = Timestamp{r.Begin.Add(time.Duration(ms * float64(time.Millisecond)))}
+			begin = r.End.Time
+			continue
+		}
+		if strings.HasPrefix(line, "Bail out!") {
+			return s, fmt.Errorf("%s", line)
+		}
+		m := tapResult.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		r := Run{Name: m[3], Verdict: "pass", Begin: Timestamp{begin}, End: Timestamp{begin}}
+		if r.Name == "" {
+			r.Name = "test " + m[2]
+		}
+		if m[1] != "" {
+			r.Verdict = "fail"
+		}
+		switch strings.ToUpper(m[4]) {
+		case "SKIP", "TODO":
+			r.Verdict, r.Reason = "skipped", strings.TrimSpace(m[4]+" "+m[5])
+		}
+		s.Runs = append(s.Runs, r)
+	}
+	return s, sc.Err()
+}
+
+var (
+	titanExecuting = regexp.MustCompile(`Executing test case (\S+) in module (\S+)\.`)
+	titanStarted   = regexp.MustCompile(`Test case (\S+) started\.`)
+	titanFinished  = regexp.MustCompile(`Test case (\S+) finished\. Verdict: (\w+)(?: reason: (.*))?`)
+	titanTime      = regexp.MustCompile(`^(\d{4}/\w{3}/\d{2} \d{2}:\d{2}:\d{2}\.\d+|\d+\.\d+|\d{2}:\d{2}:\d{2}\.\d+) `)
+)
+
+// ImportTitan imports verdicts from Titan executor logs. Timestamps in the
+// formats DateTime, Seconds and Time are supported.
+func ImportTitan(b []byte, base time.Time) (*Session, error) {
+	s := &Session{Id: sessionID(b)}
+	var (
+		modules = make(map[string]string)
+		begins  = make(map[string]time.Time)
+	)
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		t := titanTimestamp(line, base)
+		if m := titanExecuting.FindStringSubmatch(line); m != nil {
+			modules[m[1]] = m[2]
+		}
+		if m := titanStarted.FindStringSubmatch(line); m != nil {
+			begins[m[1]] = t
+		}
+		if m := titanFinished.FindStringSubmatch(line); m != nil {
+			name := m[1]
+			if mod, ok := modules[name]; ok {
+				name = mod + "." + name
+			}
+			begin, ok := begins[m[1]]
+			if !ok {
+				begin = t
+			}
+			s.Runs = append(s.Runs, Run{
+				Name:    name,
+				Verdict: m[2],
+				Reason:  strings.TrimSpace(m[3]),
+				Begin:   Timestamp{begin},
+				End:     Timestamp{t},
+			})
+		}
+	}
+	return s, sc.Err()
+}
+
+// titanTimestamp returns the timestamp of a log line. Timestamps without a
+// date use the date of base. Lines without timestamp return base.
+func titanTimestamp(line string, base time.Time) time.Time {
+	m := titanTime.FindStringSubmatch(line)
+	if m == nil {
+		return base
+	}
+	if t, err := time.ParseInLocation("2006/Jan/02 15:04:05.999999999", m[1], time.Local); err == nil {
+		return t
+	}
+	if f, err := strconv.ParseFloat(m[1], 64); err == nil {
+		return time.Unix(0, int64(f*float64(time.Second)))
+	}
+	if t, err := time.ParseInLocation("15:04:05.999999999", m[1], time.Local); err == nil {
+		y, mo, d := base.Date()
+		return time.Date(y, mo, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+	}
+	return base
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,106 @@
+package results
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportJUnit(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="M" timestamp="2024-03-01T12:00:00">
+    <testcase name="TC1" time="1.5"/>
+    <testcase name="TC2" classname="N" time="2">
+      <failure message="assertion failed">details</failure>
+    </testcase>
+    <testcase name="TC3" time="0"><error>crash</error></testcase>
+    <testcase name="TC4"><skipped/></testcase>
+  </testsuite>
+</testsuites>`
+
+	s, err := ImportJUnit([]byte(input), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{
+		"pass	M.TC1-0	1.5s",
+		"fail	N.TC2-0	2s",
+		"error	M.TC3-0	0s",
+		"skipped	M.TC4-0	0s",
+	}, stringSlice(s.Runs))
+	assert.Equal(t, "assertion failed", s.Runs[1].Reason)
+	assert.Equal(t, "crash", s.Runs[2].Reason)
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 1, 500000000, time.Local), s.Runs[1].Begin.Time)
+
+	format, err := DetectFormat("report", []byte(input))
+	assert.Nil(t, err)
+	assert.Equal(t, "junit", format)
+}
+
+func TestImportTAP(t *testing.T) {
+	input := `TAP version 13
+1..5
+ok 1 - M.TC1
+not ok 2 - M.TC2
+  ---
+  duration_ms: 250
+  ...
+ok 3 M.TC3 # SKIP not supported
+not ok 4 - M.TC4 # TODO not implemented
+ok 5
+`
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	s, err := ImportTAP([]byte(input), base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{
+		"pass	M.TC1-0	0s",
+		"fail	M.TC2-0	250ms",
+		"skipped	M.TC3-0	0s",
+		"skipped	M.TC4-0	0s",
+		"pass	test 5-0	0s",
+	}, stringSlice(s.Runs))
+	assert.Equal(t, "SKIP not supported", s.Runs[2].Reason)
+	assert.Equal(t, base.Add(250*time.Millisecond), s.Runs[2].Begin.Time)
+
+	_, err = ImportTAP([]byte("ok 1 - A\nBail out! no database\n"), base)
+	assert.ErrorContains(t, err, "no database")
+
+	format, err := DetectFormat("out.txt", []byte(input))
+	assert.Nil(t, err)
+	assert.Equal(t, "tap", format)
+}
+
+func TestImportTitan(t *testing.T) {
+	input := `2024/Mar/01 12:00:00.000000 EXECUTOR_RUNTIME Executing test case TC1 in module M.
+2024/Mar/01 12:00:00.100000 TESTCASE Test case TC1 started.
+2024/Mar/01 12:00:01.100000 TESTCASE Test case TC1 finished. Verdict: pass
+2024/Mar/01 12:00:01.200000 TESTCASE Test case TC2 started.
+2024/Mar/01 12:00:03.200000 TESTCASE Test case TC2 finished. Verdict: fail reason: timeout
+`
+	s, err := ImportTitan([]byte(input), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{
+		"pass	M.TC1-0	1s",
+		"fail	TC2-0	2s",
+	}, stringSlice(s.Runs))
+	assert.Equal(t, "timeout", s.Runs[1].Reason)
+	assert.Equal(t, time.Date(2024, 3, 1, 12, 0, 0, 100000000, time.Local), s.Runs[0].Begin.Time)
+
+	// Time format uses the date of base.
+	s, err = ImportTitan([]byte("12:00:00.000000 Test case TC1 started.\n12:00:02.500000 Test case TC1 finished. Verdict: inconc\n"),
+		time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"inconc	TC1-0	2.5s"}, stringSlice(s.Runs))
+
+	format, err := DetectFormat("mtc.log", []byte(input))
+	assert.Nil(t, err)
+	assert.Equal(t, "titan", format)
+}
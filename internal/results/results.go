@@ -16,8 +16,15 @@ import (
 // File name of the test results file
 var Filename = "test_results.json"
 
+// Latest reads the test results file Filename.
 func Latest() (*DB, error) {
-	b, err := fs.Open(Filename).Bytes()
+	return ReadFile(Filename)
+}
+
+// ReadFile reads a test results file. A missing file results in an empty
+// database.
+func ReadFile(file string) (*DB, error) {
+	b, err := fs.Open(file).Bytes()
 	if err != nil {
 		if os.IsNotExist(err) {
 			return &DB{}, nil
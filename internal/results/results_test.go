@@ -90,3 +90,17 @@ func TestFinalVerdict(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestFlakinessScores(t *testing.T) {
+	sessions := []Session{
+		{Runs: []Run{run("pass", "A-0"), run("pass", "B-0"), run("pass", "C-0"), run("fail", "D-0")}},
+		{Runs: []Run{run("pass", "A-0"), run("fail", "B-0"), run("fail", "C-0"), run("pass", "C-1"), run("fail", "D-0")}},
+		{Runs: []Run{run("pass", "A-0"), run("pass", "B-0"), run("pass", "C-0"), run("skipped", "D-0")}},
+	}
+	assert.Equal(t, []Flakiness{
+		{Name: "B", Score: 2.0 / 3, Sessions: 3, Flips: 2},
+		{Name: "C", Score: 1.0 / 3, Sessions: 3, Unstable: 1},
+		{Name: "A", Score: 0, Sessions: 3},
+		{Name: "D", Score: 0, Sessions: 2},
+	}, FlakinessScores(sessions...))
+}
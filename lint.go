@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/nokia/ntt/project"
 	"github.com/nokia/ntt/ttcn3"
 	"github.com/nokia/ntt/ttcn3/doc"
+	"github.com/nokia/ntt/ttcn3/metrics"
 	"github.com/nokia/ntt/ttcn3/syntax"
 	"github.com/spf13/cobra"
 )
@@ -148,59 +150,62 @@ For information on writing new checks, see <TBD>.
 	usedModules  = make(map[string]Import)
 	usedModuleMu sync.Mutex
 
-	style = struct {
-		MaxLines        int  `yaml:"max_lines"`
-		AlignedBraces   bool `yaml:"aligned_braces"`
-		RequireCaseElse bool `yaml:"require_case_else"`
-		Complexity      struct {
-			Max          int
-			IgnoreGuards bool `yaml:"ignore_guards"`
-		}
-		Naming struct {
-			Modules               map[string]string
-			Tests                 map[string]string
-			Functions             map[string]string
-			Altsteps              map[string]string
-			Parameters            map[string]string
-			ComponentVars         map[string]string `yaml:"component_vars"`
-			ComponentVarTemplates map[string]string `yaml:"component_var_templates"`
-			VarTemplates          map[string]string `yaml:"var_templates"`
-			PortTypes             map[string]string `yaml:"port_types"`
-			Ports                 map[string]string
-			GlobalConsts          map[string]string `yaml:"global_consts"`
-			ComponentConsts       map[string]string `yaml:"component_consts"`
-			Templates             map[string]string
-			Locals                map[string]string
-			Record                map[string]string
-			RecordFields          map[string]string `yaml:"record_fields"`
-			RecordOf              map[string]string `yaml:"record_of"`
-			Set                   map[string]string
-			SetFields             map[string]string `yaml:"set_fields"`
-			SetOf                 map[string]string `yaml:"set_of"`
-			Union                 map[string]string
-			UnionFields           map[string]string `yaml:"union_fields"`
-			Enum                  map[string]string
-			EnumLabels            map[string]string `yaml:"enum_labels"`
-		}
-		Tags struct {
-			Modules map[string]string
-			Tests   map[string]string
-		}
-		Ignore struct {
-			Modules []string
-			Files   []string
-		}
-		Usage map[string]*struct {
-			Text  string
-			Limit int
-			count int
-		}
-		Unused struct {
-			Modules bool
-		}
-	}{}
+	style lintStyle
 )
 
+// lintStyle is the lint configuration of a test suite.
+type lintStyle struct {
+	MaxLines        int  `yaml:"max_lines"`
+	AlignedBraces   bool `yaml:"aligned_braces"`
+	RequireCaseElse bool `yaml:"require_case_else"`
+	Complexity      struct {
+		Max          int
+		IgnoreGuards bool `yaml:"ignore_guards"`
+	}
+	Naming struct {
+		Modules               map[string]string
+		Tests                 map[string]string
+		Functions             map[string]string
+		Altsteps              map[string]string
+		Parameters            map[string]string
+		ComponentVars         map[string]string `yaml:"component_vars"`
+		ComponentVarTemplates map[string]string `yaml:"component_var_templates"`
+		VarTemplates          map[string]string `yaml:"var_templates"`
+		PortTypes             map[string]string `yaml:"port_types"`
+		Ports                 map[string]string
+		GlobalConsts          map[string]string `yaml:"global_consts"`
+		ComponentConsts       map[string]string `yaml:"component_consts"`
+		Templates             map[string]string
+		Locals                map[string]string
+		Record                map[string]string
+		RecordFields          map[string]string `yaml:"record_fields"`
+		RecordOf              map[string]string `yaml:"record_of"`
+		Set                   map[string]string
+		SetFields             map[string]string `yaml:"set_fields"`
+		SetOf                 map[string]string `yaml:"set_of"`
+		Union                 map[string]string
+		UnionFields           map[string]string `yaml:"union_fields"`
+		Enum                  map[string]string
+		EnumLabels            map[string]string `yaml:"enum_labels"`
+	}
+	Tags struct {
+		Modules map[string]string
+		Tests   map[string]string
+	}
+	Ignore struct {
+		Modules []string
+		Files   []string
+	}
+	Usage map[string]*struct {
+		Text  string
+		Limit int
+		count int
+	}
+	Unused struct {
+		Modules bool
+	}
+}
+
 type Import struct {
 	Node     *syntax.ImportDecl
 	Tree     *ttcn3.Tree
@@ -213,26 +218,54 @@ func init() {
 }
 
 func lint(cmd *cobra.Command, args []string) error {
-	c := fs.Open(config)
-	b, err := c.Bytes()
+	groups, err := suiteGroups(project.Files)
 	if err != nil {
-		log.Verbose(err.Error())
-		return nil
+		return err
 	}
 
-	if err := yaml.Unmarshal(b, &style); err != nil {
-		return err
+	explicit := cmd.Flags().Changed("config")
+	for _, g := range groups {
+		// Every test suite might have its own configuration.
+		b, err := fs.Open(lintConfig(g.Suite, explicit)).Bytes()
+		if err != nil {
+			log.Verbose(err.Error())
+			continue
+		}
+		style = lintStyle{}
+		if err := yaml.Unmarshal(b, &style); err != nil {
+			return err
+		}
+		if err := buildRegexCache(); err != nil {
+			return err
+		}
+		usedModules = make(map[string]Import)
+
+		printSuiteHeader(os.Stdout, g)
+		lintFiles(g.Files)
+		checkConf(g.Suite)
 	}
 
-	if err := buildRegexCache(); err != nil {
-		return err
+	switch issues {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("1 issue found.")
+	default:
+		return fmt.Errorf("%d issues found.", issues)
 	}
+}
 
-	files, err := project.Files(Project)
-	if err != nil {
-		return err
+// lintConfig returns the path of the lint configuration file of a test suite.
+// The lint file of the suite is used, unless a configuration file is given
+// explicitly or the default configuration file exists.
+func lintConfig(suite *project.Config, explicit bool) string {
+	if !explicit && !fs.IsRegular(config) && suite.LintFile != "" {
+		return suite.LintFile
 	}
+	return config
+}
 
+func lintFiles(files []string) {
 	var wg sync.WaitGroup
 	wg.Add(len(files))
 
@@ -271,6 +304,8 @@ func lint(cmd *cobra.Command, args []string) error {
 
 					stack = append(stack, n)
 
+					cc[ccID] += metrics.Decisions(n, style.Complexity.IgnoreGuards)
+
 					switch n := n.(type) {
 
 					case *syntax.Ident:
@@ -423,39 +458,10 @@ func lint(cmd *cobra.Command, args []string) error {
 						// Reset ID for counting cyclomatic complexity.
 						ccID = mod
 
-					case *syntax.BinaryExpr:
-						if n.Op.Kind() == syntax.AND || n.Op.Kind() == syntax.OR {
-							cc[ccID]++
-						}
-
-					case *syntax.IfStmt:
-						cc[ccID]++
-
 					case *syntax.CaseClause:
 						if p, ok := tree.ParentOf(n).(*syntax.SelectStmt); ok && isCaseElse(n) {
 							caseElse[p]++
-						} else {
-							// Do not count case else for complexity
-							cc[ccID]++
-						}
-
-					case *syntax.CommClause:
-						if style.Complexity.IgnoreGuards {
-							return true
-						}
-
-						// Do not count else-guards
-						if n.Else != nil {
-							return true
-						}
-						// Every AltGuard increases cyclomatic complexity.
-						cc[ccID]++
-
-						// Every AltGuard expressions also increases complexity.
-						if n.X != nil {
-							cc[ccID]++
 						}
-
 					}
 					return true
 				})
@@ -467,18 +473,6 @@ func lint(cmd *cobra.Command, args []string) error {
 	}
 
 	wg.Wait()
-
-	checkConf(Project)
-
-	switch issues {
-	case 0:
-		return nil
-	case 1:
-		return fmt.Errorf("1 issue found.")
-	default:
-		return fmt.Errorf("%d issues found.", issues)
-	}
-
 }
 
 func checkNaming(n syntax.Node, patterns map[string]string) {
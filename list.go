@@ -109,6 +109,32 @@ If a basket is not defined by an environment variable, it's equivalent to a
 	# This does the same:
 	$ ntt list --tags-regex="@wip|@flaky"
 
+
+Named Baskets
+-------------
+
+Baskets may also be defined in the baskets section of the manifest
+(package.yml), either by filters or by an expression composing other
+baskets:
+
+	baskets:
+	  stable: "-X @wip|@flaky"
+	  sanity: "/^sanity\\./"
+	  smoke:  "sanity + @priority: high - @slow"
+
+Expression terms are tags with optional value patterns, regular expressions
+for names enclosed by slashes or names of other baskets. Terms are evaluated
+from left to right: "+" adds the matching objects, "-" removes them.
+
+The basket "failed" contains the tests, which did not pass in the last
+session of the test results, unless it is defined otherwise.
+
+Use --basket to list the objects of named baskets. Multiple baskets are ORed.
+The --basket flag replaces the baskets listed in NTT_LIST_BASKETS:
+
+	$ ntt list --basket smoke
+	$ ntt list --basket failed
+
 `,
 
 		// Listing tests is the default command
@@ -122,6 +148,10 @@ If a basket is not defined by an environment variable, it's equivalent to a
 	formatJSON  = false
 	formatPlain = true
 	first       = true
+
+	// suiteName is the name of the test suite being listed in
+	// workspace mode.
+	suiteName string
 )
 
 func init() {
@@ -131,6 +161,7 @@ func init() {
 	flags.BoolVarP(&showTags, "tags", "t", false, "Print documentation tags for each match.")
 	flags.MarkDeprecated("tags", "please use --with-tags instead")
 	flags.AddFlagSet(BasketFlags())
+	flags.StringSlice("basket", nil, "list objects of named basket `NAME`")
 	ListCommand.AddCommand(
 		&cobra.Command{Use: `tests`, RunE: list},
 		&cobra.Command{Use: `modules`, RunE: list},
@@ -144,10 +175,12 @@ func init() {
 func list(cmd *cobra.Command, args []string) error {
 
 	basket, err := NewBasketWithFlags("list", cmd.Flags())
-	basket.LoadFromEnvOrConfig(Project, "NTT_LIST_BASKETS")
 	if err != nil {
 		return err
 	}
+	if err := loadBaskets(&basket, Project, cmd.Flags()); err != nil {
+		return err
+	}
 
 	formatJSON, _ = cmd.Flags().GetBool("json")
 	formatPlain, _ = cmd.Flags().GetBool("plain")
@@ -156,8 +189,28 @@ func list(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(w, "[")
 	}
 
-	files, err := filesOfInterest(cmd.Use, Project)
-	for _, f := range files {
+	groups, err := suiteGroups(func(c *project.Config) ([]string, error) {
+		return filesOfInterest(cmd.Use, c)
+	})
+	for _, g := range groups {
+		if err := listGroup(cmd, basket, g); err != nil {
+			return err
+		}
+	}
+
+	if formatJSON {
+		fmt.Fprintln(w, "]")
+	}
+	w.Flush()
+	return err
+}
+
+func listGroup(cmd *cobra.Command, basket Basket, g project.Group) error {
+	printSuiteHeader(w, g)
+	if workspaceMode {
+		suiteName = g.Suite.Name
+	}
+	for _, f := range g.Files {
 		tree := ttcn3.ParseFile(f)
 		if tree.Err != nil {
 			return tree.Err
@@ -202,15 +255,11 @@ func list(cmd *cobra.Command, args []string) error {
 			return false
 		})
 	}
-
-	if formatJSON {
-		fmt.Fprintln(w, "]")
-	}
-	w.Flush()
-	return err
+	return nil
 }
 
 type Match struct {
+	Suite    string `json:"suite,omitempty"`
 	Filename string `json:"filename,omitempty"`
 	Line     int    `json:"line,omitempty"`
 	Column   int    `json:"column,omitempty"`
@@ -257,6 +306,7 @@ func Print(basket Basket, n syntax.Node, id string) {
 		}
 		first = false
 		b, err := json.Marshal(Match{
+			Suite:    suiteName,
 			Filename: filename,
 			Line:     p.Line,
 			Column:   p.Column,
@@ -50,13 +50,21 @@ var (
 			}
 
 			// Skip opening the project if we're running a custom command or version.
-			if cmd.Use == "ntt" || cmd.Use == "version" || cmd.Use == "stdout" || strings.HasPrefix(cmd.Use, "help") || cmd.Use == "docs" || cmd.Use == "objdump" || cmd.Use == "t3xfasm" {
+			if cmd.Annotations[projectArgs] == projectArgsSkip || cmd.Use == "ntt" || cmd.Use == "version" || cmd.Use == "stdout" || strings.HasPrefix(cmd.Use, "help") || cmd.Use == "docs" || cmd.Use == "objdump" || cmd.Use == "t3xfasm" {
 				// first arg is either an external subkommand of the form
 				// k3-Arg[0] or ntt-Arg[0] or unknown
 				return nil
 			}
 
 			files, _ := splitArgs(args, cmd.ArgsLenAtDash())
+			switch cmd.Annotations[projectArgs] {
+			case projectArgsNone:
+				files = nil
+			case projectArgsAllButLast:
+				if len(files) > 0 {
+					files = files[:len(files)-1]
+				}
+			}
 			p, err := project.Open(files...)
 			if err != nil {
 				return err
@@ -92,6 +100,7 @@ var (
 	verbose        int
 	ShSetup        bool
 	dumb           bool
+	explain        bool
 	outputQuiet    bool
 	outputJSON     bool
 	outputPlain    bool
@@ -109,6 +118,20 @@ var (
 	Project *project.Config
 )
 
+// The projectArgs annotation of a command tells which command line arguments
+// are used to open the project:
+//
+//	""              all arguments are source files (default)
+//	"all-but-last"  all but the last argument, which is a testcase for example
+//	"none"          none, the project is opened in the current directory
+//	"skip"          the project is not opened at all
+const (
+	projectArgs           = "project-args"
+	projectArgsAllButLast = "all-but-last"
+	projectArgsNone       = "none"
+	projectArgsSkip       = "skip"
+)
+
 func init() {
 	root := RootCommand
 	flags := root.PersistentFlags()
@@ -121,18 +144,33 @@ func init() {
 
 	RootCommand.Flags().BoolP("interactive", "i", false, "run in interactive mode")
 
+	root.AddCommand(AffectedCommand)
+	root.AddCommand(CheckConfigCommand)
 	root.AddCommand(CompileCommand)
+	root.AddCommand(CompdbCommand)
+	root.AddCommand(DepsCommand)
 	root.AddCommand(DumpCommand)
+	root.AddCommand(ExportCommand)
+	root.AddCommand(ExtractCommand)
+	root.AddCommand(FlakyCommand)
 	root.AddCommand(FormatCommand)
+	root.AddCommand(GraphCommand)
+	root.AddCommand(ImportResultsCommand)
+	root.AddCommand(ImportsCommand)
+	root.AddCommand(InitCommand)
 	root.AddCommand(LangserverCommand)
 	root.AddCommand(LintCommand)
 	root.AddCommand(ListCommand)
+	root.AddCommand(MetricsCommand)
+	root.AddCommand(ParametersCommand)
+	root.AddCommand(PlanCommand)
 	root.AddCommand(ReportCommand)
 	root.AddCommand(ShowCommand)
 	root.AddCommand(TagsCommand)
 
 	ShowCommand.PersistentFlags().BoolVarP(&ShSetup, "sh", "", false, "output test suite data for shell consumption")
 	ShowCommand.PersistentFlags().BoolVarP(&dumb, "dumb", "", false, "do not evaluate testcase configuration")
+	ShowCommand.PersistentFlags().BoolVarP(&explain, "explain", "", false, "explain where configuration values come from")
 }
 
 func Format() string {
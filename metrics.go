@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/yaml"
+	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/metrics"
+	"github.com/spf13/cobra"
+)
+
+var (
+	MetricsCommand = &cobra.Command{
+		Use:   "metrics",
+		Short: "Report size and complexity metrics",
+		Long: `Report size and complexity metrics.
+
+The metrics command reports metrics for every module and every module
+definition of the test suite:
+
+  loc           lines of code, without blank lines and comment lines.
+  complexity    cyclomatic complexity of behaviours. The complexity of a
+                module is the sum of all its behaviours. Guards are ignored
+                if complexity.ignore_guards is set in the lint configuration.
+  nesting       maximum nesting depth of statement blocks.
+  fan_in        number of definitions (modules) referencing the definition
+                (module).
+  fan_out       number of definitions (modules) referenced by the definition
+                (module).
+  alt_branches  number of alternatives in alt-statements, interleave-statements
+                and altsteps.
+  templates     number of templates referenced by a definition, or number of
+                templates defined by a module.
+
+Output is a human readable table by default. Use --csv or --json for further
+processing, for example to track trends over releases.
+`,
+		RunE: printMetrics,
+	}
+
+	outputCSV   bool
+	modulesOnly bool
+)
+
+func init() {
+	MetricsCommand.Flags().BoolVarP(&outputCSV, "csv", "", false, "output in CSV format")
+	MetricsCommand.Flags().BoolVarP(&modulesOnly, "modules", "m", false, "report modules only")
+}
+
+func printMetrics(cmd *cobra.Command, args []string) error {
+	files, err := fs.TTCN3Files(Project.Sources...)
+	if err != nil {
+		return err
+	}
+	all, err := project.Files(Project)
+	if err != nil {
+		return err
+	}
+
+	var db ttcn3.DB
+	db.Index(all...)
+	// Complexity is computed like the lint command does.
+	var opts metrics.Options
+	if b, err := fs.Open(lintConfig(Project, false)).Bytes(); err == nil {
+		if err := yaml.Unmarshal(b, &style); err != nil {
+			return err
+		}
+		opts.IgnoreGuards = style.Complexity.IgnoreGuards
+	}
+	mods := metrics.Compute(&db, opts, files...)
+	if modulesOnly {
+		for _, m := range mods {
+			m.Definitions = nil
+		}
+	}
+
+	switch {
+	case outputJSON:
+		b, err := json.MarshalIndent(mods, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+
+	case outputCSV:
+		w := csv.NewWriter(os.Stdout)
+		w.Write(append([]string{"kind", "name", "file", "line"}, metricsHeader...))
+		for _, m := range mods {
+			w.Write(append([]string{"module", m.Name, m.File, ""}, metricsRecord(m.Metrics)...))
+			for _, d := range m.Definitions {
+				w.Write(append([]string{d.Kind, d.Name, d.File, strconv.Itoa(d.Line)}, metricsRecord(d.Metrics)...))
+			}
+		}
+		w.Flush()
+		return w.Error()
+
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "module\t"+tabbed(metricsHeader)+"\t")
+		for _, m := range mods {
+			fmt.Fprintln(w, m.Name+"\t"+tabbed(metricsRecord(m.Metrics))+"\t")
+		}
+		if !modulesOnly {
+			fmt.Fprintln(w, "\t")
+			fmt.Fprintln(w, "definition\t"+tabbed(metricsHeader)+"\t")
+			for _, m := range mods {
+				for _, d := range m.Definitions {
+					fmt.Fprintln(w, d.Name+"\t"+tabbed(metricsRecord(d.Metrics))+"\t")
+				}
+			}
+		}
+		return w.Flush()
+	}
+}
+
+var metricsHeader = []string{"loc", "complexity", "nesting", "fan_in", "fan_out", "alt_branches", "templates"}
+
+func metricsRecord(m metrics.Metrics) []string {
+	var ret []string
+	for _, v := range []int{m.LOC, m.Complexity, m.Nesting, m.FanIn, m.FanOut, m.AltBranches, m.Templates} {
+		ret = append(ret, strconv.Itoa(v))
+	}
+	return ret
+}
+
+func tabbed(s []string) string {
+	return strings.Join(s, "\t")
+}
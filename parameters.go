@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nokia/ntt/internal/env"
+	"github.com/nokia/ntt/internal/yaml"
+	"github.com/nokia/ntt/project"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/doc"
+	"github.com/nokia/ntt/ttcn3/syntax"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ParametersCommand = &cobra.Command{
+		Use:   "parameters TEST",
+		Short: "Print the effective test configuration of a testcase",
+		Long: `Print the effective test configuration of a testcase.
+
+The parameters command merges the manifest, the parameters file and all files
+they include and prints the resulting configurations for the qualified
+testcase name TEST, with module parameters, timeout and presets applied.
+Files are merged in include order: included files first, in the order they
+are listed, then the including file. A file included multiple times is merged
+only once.
+
+Presets are given with --preset or with environment variable NTT_PRESETS.
+
+Module parameters assigned differently by two files, where neither file
+includes the other, are reported as conflicts on standard error.
+`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: map[string]string{projectArgs: projectArgsNone},
+		RunE:        printParameters,
+	}
+
+	paramPresets []string
+)
+
+func init() {
+	ParametersCommand.Flags().StringSliceVarP(&paramPresets, "preset", "p", nil, "apply `PRESET`")
+}
+
+func printParameters(cmd *cobra.Command, args []string) error {
+	presets := paramPresets
+	if s := env.Getenv("NTT_PRESETS"); s != "" && len(presets) == 0 {
+		presets = strings.Split(s, string(os.PathListSeparator))
+	}
+
+	for _, c := range Project.Conflicts() {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", c.String())
+	}
+
+	tcs, err := Project.TestConfigsFor(findTest(args[0]), presets...)
+	if err != nil {
+		return err
+	}
+	if tcs == nil {
+		tcs = []project.TestConfig{}
+	}
+
+	if outputJSON {
+		b, err := yaml.MarshalJSON(tcs)
+		if err != nil {
+			return err
+		}
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		b, err = json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	b, err := yaml.Marshal(tcs)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(b)
+	return err
+}
+
+// findTest returns the testcase with the given qualified name and the tags
+// of its documentation. If the testcase is not found, the tags are empty.
+func findTest(name string) project.Test {
+	test := project.Test{Name: name}
+	files, err := project.Files(Project)
+	if err != nil {
+		return test
+	}
+	for _, file := range files {
+		tree := ttcn3.ParseFile(file)
+		for _, n := range tree.Tests() {
+			if tree.QualifiedName(n.Node) == name {
+				test.Tags = doc.FindAllTags(syntax.Doc(n.Node))
+				return test
+			}
+		}
+	}
+	return test
+}
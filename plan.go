@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nokia/ntt/control"
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/results"
+	"github.com/nokia/ntt/ttcn3/doc"
+	"github.com/nokia/ntt/ttcn3/syntax"
+	"github.com/spf13/cobra"
+)
+
+var (
+	PlanCommand = &cobra.Command{
+		Use:   "plan",
+		Short: "Print the execution order of testcases",
+		Long: `Print the execution order of testcases.
+
+The plan command prints the testcases of the test suite in the order they
+should be executed: tests expected to take longest are started first, which
+reduces the total run time when tests are executed in parallel.
+
+Expected durations are the median durations recorded in the test history (see
+ntt report --record) or, without history, in the latest test results. Tests
+without recorded duration are expected to take the mean duration of all
+known tests.
+
+Use --durations to read the expected durations from a results file instead.
+
+With --shard i/n the testcases are split into n shards of similar expected
+run time and only the i-th shard is printed. The split is deterministic for
+the same testcases and expected durations, which allows to distribute a test
+suite across multiple machines. All machines must use the same durations,
+because shards computed from different histories may overlap or miss tests.
+Pass a shared results file with --durations:
+
+	# On machine 2 of 4
+	k3 run $(ntt plan --shard 2/4 --durations durations.json)
+
+Testcases can be selected with the same filters and baskets as ntt list.
+`,
+		Args: cobra.NoArgs,
+		RunE: plan,
+	}
+
+	planShard     string
+	planDurations string
+)
+
+func init() {
+	PlanCommand.Flags().StringVar(&planShard, "shard", "", "print only the testcases of shard `i/n`")
+	PlanCommand.Flags().StringVar(&planDurations, "durations", "", "read expected durations from results `FILE`")
+	PlanCommand.Flags().AddFlagSet(BasketFlags())
+	PlanCommand.Flags().StringSlice("basket", nil, "plan testcases of named basket `NAME`")
+}
+
+func plan(cmd *cobra.Command, args []string) error {
+	basket, err := NewBasketWithFlags("plan", cmd.Flags())
+	if err != nil {
+		return err
+	}
+	if err := loadBaskets(&basket, Project, cmd.Flags()); err != nil {
+		return err
+	}
+
+	tp, err := control.NewTestPlan(Project)
+	if err != nil {
+		return err
+	}
+	tp.Filter(func(name string, n syntax.Node) bool {
+		var tags [][]string
+		if n != nil {
+			tags = doc.FindAllTags(syntax.Doc(n))
+		}
+		return basket.Match(name, tags)
+	})
+
+	d, err := expectedDurations()
+	if err != nil {
+		return err
+	}
+	tp.Schedule(d)
+
+	if planShard != "" {
+		i, n, err := control.ParseShard(planShard)
+		if err != nil {
+			return err
+		}
+		if err := tp.Shard(i, n, d); err != nil {
+			return err
+		}
+	}
+
+	var total time.Duration
+	for _, t := range tp.Tests {
+		total += d.Expected(t)
+	}
+	log.Verbosef("%d testcases, expected run time %s\n", len(tp.Tests), total.Round(time.Second))
+
+	if outputJSON {
+		tests := tp.Tests
+		if tests == nil {
+			tests = []string{}
+		}
+		b, err := json.MarshalIndent(tests, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+	for _, t := range tp.Tests {
+		fmt.Println(t)
+	}
+	return nil
+}
+
+// expectedDurations returns the expected durations of tests recorded in the
+// results file given by --durations, in the history or, if there's no
+// history, in the latest test results.
+func expectedDurations() (control.Durations, error) {
+	if planDurations != "" {
+		if _, err := os.Stat(planDurations); err != nil {
+			return nil, err
+		}
+		db, err := results.ReadFile(planDurations)
+		if err != nil {
+			return nil, err
+		}
+		return control.Durations(results.ExpectedDurations(db.Runs())), nil
+	}
+
+	entries, err := results.OpenHistory(historyFile()).Entries()
+	if err != nil {
+		return nil, err
+	}
+	runs := results.EntryRuns(entries...)
+	if len(runs) == 0 {
+		db, err := latestResults()
+		if err != nil {
+			return nil, err
+		}
+		runs = db.Runs()
+	}
+	return control.Durations(results.ExpectedDurations(runs)), nil
+}
@@ -0,0 +1,468 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/nokia/ntt/internal/env"
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/yaml"
+)
+
+// A Problem describes an issue found in a configuration file.
+type Problem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Line, p.Column, p.Message)
+}
+
+// CheckManifest validates a manifest file and the parameters file it refers
+// to. Unlike WithManifest, CheckManifest does not stop at the first error, but
+// reports all problems found:
+//
+//   - unknown keys
+//   - values of the wrong type
+//   - unresolved variable references (${VAR})
+//   - sources and imports which do not exist
+//   - presets referenced by execute entries, which are not defined
+//   - included parameters files, which do not exist
+//   - module parameters assigned differently by included files
+//
+// An error is returned if a file cannot be read or is not valid YAML.
+func CheckManifest(file string) ([]Problem, error) {
+	c := checker{
+		root:    filepath.Dir(file),
+		presets: make(map[string]bool),
+	}
+
+	root, err := c.parse(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var conf Config
+	conf.Variables = c.variables(root)
+	conf.updateVariables("")
+	c.vars = conf.Variables
+	c.walk(root, reflect.TypeOf(Manifest{}), "")
+
+	if params := c.parametersFile(root); params != "" {
+		root, err := c.parse(params)
+		if err != nil {
+			return nil, err
+		}
+		c.walk(root, reflect.TypeOf(Parameters{}), "")
+	}
+
+	// Included files are checked like parameters files.
+	checked := map[string]bool{file: true}
+	for len(c.includes) > 0 {
+		inc := c.includes[0]
+		c.includes = c.includes[1:]
+		if checked[inc] {
+			continue
+		}
+		checked[inc] = true
+		root, err := c.parse(inc)
+		if err != nil {
+			return nil, err
+		}
+		c.walk(root, reflect.TypeOf(Parameters{}), "")
+	}
+
+	// Conflicts require a loadable configuration. If it cannot be loaded,
+	// the reason has been reported already.
+	if conf, err := NewConfig(WithManifest(file), WithDefaults()); err == nil {
+		for _, x := range conf.Conflicts() {
+			c.reportConflict(x)
+		}
+	}
+
+	for _, r := range c.presetRefs {
+		if !c.presets[r.name] {
+			c.reportAt(r.file, r.node, "unknown preset %q", r.name)
+		}
+	}
+
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		a, b := c.problems[i], c.problems[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return c.problems, nil
+}
+
+type checker struct {
+	file     string
+	root     string
+	vars     env.Env
+	problems []Problem
+
+	// presets are the defined presets and presetRefs the presets
+	// referenced by execute entries. References are resolved after all
+	// files have been checked.
+	presets    map[string]bool
+	presetRefs []presetRef
+
+	// includes are the included parameters files not checked yet.
+	includes []string
+}
+
+type presetRef struct {
+	file string
+	name string
+	node ast.Node
+}
+
+// parse parses file and returns the body of its first document.
+func (c *checker) parse(file string) (ast.Node, error) {
+	b, err := fs.Content(file)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parser.ParseBytes(b, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	c.file = file
+	if len(f.Docs) == 0 {
+		return nil, nil
+	}
+	return f.Docs[0].Body, nil
+}
+
+func (c *checker) report(n ast.Node, format string, args ...interface{}) {
+	c.reportAt(c.file, n, format, args...)
+}
+
+func (c *checker) reportAt(file string, n ast.Node, format string, args ...interface{}) {
+	p := Problem{File: file, Message: fmt.Sprintf(format, args...)}
+	if tok := n.GetToken(); tok != nil {
+		p.Line = tok.Position.Line
+		p.Column = tok.Position.Column
+	}
+	c.problems = append(c.problems, p)
+}
+
+// variables returns the variables section of a manifest.
+func (c *checker) variables(root ast.Node) env.Env {
+	vars := make(env.Env)
+	if n := lookup(root, "variables"); n != nil {
+		forEach(n, func(k string, _, v ast.Node) {
+			if s, ok := scalar(v); ok {
+				vars[k] = s
+			}
+		})
+	}
+	return vars
+}
+
+// parametersFile returns the path of the parameters file used by the
+// manifest or an empty string if there is none.
+func (c *checker) parametersFile(root ast.Node) string {
+	if n := lookup(root, "parameters_file"); n != nil {
+		if s, ok := scalar(n); ok {
+			if s, err := env.Expand(s, c.copyVars()); err == nil {
+				return fs.Real(c.root, s)
+			}
+		}
+		return ""
+	}
+
+	name := filepath.Base(c.root)
+	if n := lookup(root, "name"); n != nil {
+		if s, ok := scalar(n); ok {
+			name = s
+		}
+	}
+	if path := fs.JoinPath(c.root, name+".parameters"); fs.IsRegular(path) {
+		return path
+	}
+	return ""
+}
+
+// walk checks that node n matches type t. Path is the dotted key path of n
+// and used to identify values requiring additional checks.
+func (c *checker) walk(n ast.Node, t reflect.Type, path string) {
+	n = unwrap(n)
+	if n == nil || n.Type() == ast.NullType || n.Type() == ast.AliasType {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		s, ok := scalar(n)
+		if _, err := strconv.ParseFloat(s, 64); !ok || err != nil {
+			c.report(n, "%s: expected number of seconds, got %s", path, describe(n))
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if !isMapping(n) {
+			c.report(n, "%s: expected mapping, got %s", path, describe(n))
+			return
+		}
+		fields := structFields(t)
+		forEach(n, func(k string, kn, v ast.Node) {
+			ft, ok := fields[k]
+			if !ok {
+				c.report(kn, "unknown key %q", join(path, k))
+				return
+			}
+			c.walk(v, ft, join(path, k))
+		})
+
+	case reflect.Map:
+		if !isMapping(n) {
+			c.report(n, "%s: expected mapping, got %s", path, describe(n))
+			return
+		}
+		forEach(n, func(k string, _, v ast.Node) {
+			if path == "presets" {
+				c.presets[k] = true
+			}
+			c.walk(v, t.Elem(), join(path, k))
+		})
+
+	case reflect.Slice:
+		seq, ok := n.(*ast.SequenceNode)
+		if !ok {
+			c.report(n, "%s: expected sequence, got %s", path, describe(n))
+			return
+		}
+		for i, v := range seq.Values {
+			c.walk(v, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+
+	case reflect.String:
+		s, ok := scalar(n)
+		if !ok {
+			c.report(n, "%s: expected string, got %s", path, describe(n))
+			return
+		}
+		c.checkString(n, s, path)
+
+	case reflect.Int, reflect.Int64:
+		if n.Type() != ast.IntegerType {
+			c.report(n, "%s: expected integer, got %s", path, describe(n))
+		}
+
+	case reflect.Bool:
+		if n.Type() != ast.BoolType {
+			c.report(n, "%s: expected boolean, got %s", path, describe(n))
+		}
+	}
+}
+
+// checkString checks variable references of a string value and, depending on
+// its path, whether it references existing files or presets.
+func (c *checker) checkString(n ast.Node, s, path string) {
+	s, err := env.Expand(s, c.copyVars())
+	if err != nil {
+		c.report(n, "%s: %s", path, err.Error())
+	}
+
+	key := path
+	if i := strings.LastIndex(path, "["); i >= 0 {
+		key = path[:i]
+	}
+	switch {
+	case key == "sources" || key == "imports":
+		if err != nil || fs.IsURI(s) {
+			return
+		}
+		if _, err := os.Stat(fs.Real(c.root, s)); errors.Is(err, os.ErrNotExist) {
+			c.report(n, "%s: %s does not exist", path, s)
+		}
+	case key == "include":
+		if err != nil {
+			return
+		}
+		inc := fs.Real(filepath.Dir(c.file), s)
+		if !fs.IsRegular(inc) {
+			c.report(n, "%s: %s does not exist", path, s)
+			return
+		}
+		c.includes = append(c.includes, inc)
+	case strings.HasPrefix(key, "execute[") && strings.HasSuffix(key, ".preset"):
+		c.presetRefs = append(c.presetRefs, presetRef{file: c.file, name: s, node: n})
+	}
+}
+
+// reportConflict reports a conflict at the module parameter assignment of
+// the file merged last.
+func (c *checker) reportConflict(x Conflict) {
+	file := x.Files[1]
+	p := Problem{File: file, Message: fmt.Sprintf("module parameter %s: %q conflicts with %q from %s", x.Name, x.Values[1], x.Values[0], x.Files[0])}
+	if x.Preset != "" {
+		p.Message = fmt.Sprintf("presets.%s: %s", x.Preset, p.Message)
+	}
+	if root, err := c.parse(file); err == nil {
+		n := root
+		if x.Preset != "" {
+			n = lookup(lookup(n, "presets"), x.Preset)
+		}
+		if n = lookup(lookup(n, "parameters"), x.Name); n != nil {
+			if tok := n.GetToken(); tok != nil {
+				p.Line = tok.Position.Line
+				p.Column = tok.Position.Column
+			}
+		}
+	}
+	c.problems = append(c.problems, p)
+}
+
+func (c *checker) copyVars() env.Env {
+	vars := make(env.Env, len(c.vars))
+	for k, v := range c.vars {
+		vars[k] = v
+	}
+	return vars
+}
+
+var durationType = reflect.TypeOf(yaml.Duration{})
+
+// structFields returns the YAML keys of a struct type and their types.
+// Inlined structs are flattened.
+func structFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, inline := fieldName(f)
+		if inline {
+			for k, v := range structFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// fieldName returns the YAML key of a struct field the same way the YAML
+// decoder does: the json tag, if present, or the lower case field name.
+func fieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("yaml")
+	if tag == "" {
+		tag = f.Tag.Get("json")
+	}
+	opts := strings.Split(tag, ",")
+	name := strings.ToLower(f.Name)
+	if opts[0] != "" {
+		name = opts[0]
+	}
+	for _, opt := range opts[1:] {
+		if opt == "inline" {
+			return name, true
+		}
+	}
+	return name, false
+}
+
+// unwrap returns the value of anchor and tag nodes.
+func unwrap(n ast.Node) ast.Node {
+	for {
+		switch x := n.(type) {
+		case *ast.AnchorNode:
+			n = x.Value
+		case *ast.TagNode:
+			n = x.Value
+		default:
+			return n
+		}
+	}
+}
+
+func isMapping(n ast.Node) bool {
+	_, ok := n.(ast.MapNode)
+	return ok
+}
+
+// forEach calls f for every key value pair of mapping n.
+func forEach(n ast.Node, f func(key string, k, v ast.Node)) {
+	m, ok := unwrap(n).(ast.MapNode)
+	if !ok {
+		return
+	}
+	for it := m.MapRange(); it.Next(); {
+		k, _ := scalar(it.Key())
+		f(k, it.Key(), it.Value())
+	}
+}
+
+// lookup returns the value of key in mapping n.
+func lookup(n ast.Node, key string) ast.Node {
+	var ret ast.Node
+	forEach(n, func(k string, _, v ast.Node) {
+		if k == key {
+			ret = v
+		}
+	})
+	return ret
+}
+
+// scalar returns the string value of a scalar node.
+func scalar(n ast.Node) (string, bool) {
+	switch n := unwrap(n).(type) {
+	case *ast.StringNode:
+		return n.Value, true
+	case *ast.LiteralNode:
+		return n.Value.Value, true
+	case *ast.IntegerNode, *ast.FloatNode, *ast.BoolNode, *ast.InfinityNode, *ast.NanNode:
+		return n.GetToken().Value, true
+	case *ast.MappingKeyNode:
+		return scalar(n.Value)
+	}
+	return "", false
+}
+
+func describe(n ast.Node) string {
+	switch n.Type() {
+	case ast.MappingType, ast.MappingValueType:
+		return "mapping"
+	case ast.SequenceType:
+		return "sequence"
+	case ast.IntegerType:
+		return "integer"
+	case ast.FloatType, ast.InfinityType, ast.NanType:
+		return "number"
+	case ast.BoolType:
+		return "boolean"
+	case ast.StringType, ast.LiteralType:
+		return "string"
+	}
+	return strings.ToLower(n.Type().String())
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,104 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckManifest(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"src/a.ttcn3": "module a {}",
+		"package.yml": `name: suite
+sources:
+  - src
+  - missing.ttcn3
+  - ${SRC}/b.ttcn3
+imports: lib
+before_bulid: [make]
+timeout: 1.5
+variables:
+  FOO: bar
+  BAZ: ${FOO}/baz
+format:
+  line_width: wide
+presets:
+  fast:
+    timeout: 1
+execute:
+  - test: a.*
+    preset: [fast, slow]
+    parameters:
+      a.x: ${FOO}
+    only:
+      presets: [nightly]
+      labels: [x]
+`,
+		"suite.parameters": `timeout: [1]
+execute:
+  - test: a.y
+    preset: [fast, ${FOO}]
+`,
+	})
+
+	problems, err := CheckManifest(filepath.Join(dir, "package.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []string
+	for _, p := range problems {
+		rel, _ := filepath.Rel(dir, p.File)
+		p.File = rel
+		actual = append(actual, p.String())
+	}
+	assert.Equal(t, []string{
+		"package.yml:4:5: sources[1]: missing.ttcn3 does not exist",
+		"package.yml:5:5: sources[2]: unknown variable: SRC",
+		"package.yml:6:10: imports: expected sequence, got string",
+		"package.yml:7:1: unknown key \"before_bulid\"",
+		"package.yml:13:15: format.line_width: expected integer, got string",
+		"package.yml:19:20: unknown preset \"slow\"",
+		"package.yml:24:7: unknown key \"execute[0].only.labels\"",
+		"suite.parameters:1:10: timeout: expected number of seconds, got sequence",
+		"suite.parameters:4:20: unknown preset \"bar\"",
+	}, actual)
+}
+
+func TestCheckManifestValid(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.ttcn3": "module a {}",
+		"package.yml": `sources: [a.ttcn3]
+parameters_file: params.yml
+`,
+		"params.yml": `presets:
+  fast: {timeout: 1}
+execute:
+  - {test: a.*, preset: [fast]}
+`,
+	})
+	problems, err := CheckManifest(filepath.Join(dir, "package.yml"))
+	assert.Nil(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestSchema(t *testing.T) {
+	for file, f := range map[string]func() ([]byte, error){
+		"schema/package.schema.json":    ManifestSchema,
+		"schema/parameters.schema.json": ParametersSchema,
+	} {
+		b, err := f()
+		if err != nil {
+			t.Fatal(err)
+		}
+		published, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(published), string(b), "%s is outdated. Update it using ntt check-config --schema", file)
+	}
+}
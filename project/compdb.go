@@ -0,0 +1,73 @@
+package project
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/nokia/ntt/internal/compdb"
+	"github.com/nokia/ntt/internal/fs"
+)
+
+// DefaultCompiler is the compiler used, when the manifest does not specify
+// one. The default compiler is passed the source file only, because it does
+// not understand the define, include and diagnostics flags of vendor
+// compilers.
+var DefaultCompiler = "ntt compile"
+
+// sourceFiles returns the TTCN-3 and ASN.1 files of the given sources.
+// Directories are replaced by the source files they contain.
+func sourceFiles(paths ...string) []string {
+	var files []string
+	for _, path := range paths {
+		if !fs.IsDir(path) {
+			files = append(files, path)
+			continue
+		}
+		files = append(files, fs.FindTTCN3Files(path)...)
+		files = append(files, fs.FindASN1Files(path)...)
+	}
+	return files
+}
+
+// Commands returns a compilation database entry for every TTCN-3 and ASN.1
+// source file of the project, including imports. Config implements the
+// compdb.Commander interface.
+func (c *Config) Commands() []compdb.Command {
+	dir := absPath(c.Root)
+
+	files := append(sourceFiles(c.Sources...), sourceFiles(c.Imports...)...)
+
+	args := strings.Fields(DefaultCompiler)
+	if c.Compiler != "" {
+		args = strings.Fields(c.Compiler)
+		args = append(args, c.Diagnostics...)
+		for _, d := range c.Defines {
+			args = append(args, "-D"+d)
+		}
+		for _, d := range c.Imports {
+			if fs.IsDir(d) {
+				args = append(args, "-I"+absPath(d))
+			}
+		}
+	}
+
+	cmds := make([]compdb.Command, 0, len(files))
+	for _, f := range files {
+		f = absPath(f)
+		cmds = append(cmds, compdb.Command{
+			Directory: dir,
+			File:      f,
+			Arguments: append(append([]string(nil), args...), f),
+		})
+	}
+	return cmds
+}
+
+// absPath converts URIs and relative paths into absolute paths.
+func absPath(path string) string {
+	path = fs.Path(path)
+	if p, err := filepath.Abs(path); err == nil {
+		return p
+	}
+	return path
+}
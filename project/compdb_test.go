@@ -0,0 +1,80 @@
+package project
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nokia/ntt/internal/compdb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommands(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"suite/a.ttcn3":   `module a {}`,
+		"suite/b.asn":     `B DEFINITIONS ::= BEGIN END`,
+		"suite/README.md": ``,
+		"lib/c.ttcn":      `module c {}`,
+	})
+
+	c := &Config{}
+	c.Root = filepath.Join(dir, "suite")
+	c.Sources = []string{filepath.Join(dir, "suite")}
+	c.Imports = []string{filepath.Join(dir, "lib")}
+	c.Compiler = "k3c --strict"
+	c.Diagnostics = []string{"-w1"}
+	c.Defines = []string{"DEBUG"}
+
+	var cmdr compdb.Commander = c
+	args := func(file string) []string {
+		return []string{"k3c", "--strict", "-w1", "-DDEBUG", "-I" + filepath.Join(dir, "lib"), file}
+	}
+	a, b, cc := filepath.Join(dir, "suite/a.ttcn3"), filepath.Join(dir, "suite/b.asn"), filepath.Join(dir, "lib/c.ttcn")
+	assert.Equal(t, []compdb.Command{
+		{Directory: c.Root, File: a, Arguments: args(a)},
+		{Directory: c.Root, File: b, Arguments: args(b)},
+		{Directory: c.Root, File: cc, Arguments: args(cc)},
+	}, cmdr.Commands())
+}
+
+func TestReadIndex(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"build/ttcn3_suites.json": `{"source_dir": "..", "suites": [{"root_dir": "suite", "source_dir": "/src/suite"}]}`,
+	})
+	idx, err := ReadIndex(filepath.Join(dir, "build/ttcn3_suites.json"))
+	assert.Nil(t, err)
+	assert.Equal(t, dir, idx.SourceDir)
+	assert.Equal(t, []Suite{{RootDir: filepath.Join(dir, "build/suite"), SourceDir: "/src/suite"}}, idx.Suites)
+}
+
+func TestCommandsDefaultCompiler(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"suite/a.ttcn3": `module a {}`,
+		"lib/c.ttcn":    `module c {}`,
+	})
+
+	c := &Config{}
+	c.Root = filepath.Join(dir, "suite")
+	c.Sources = []string{filepath.Join(dir, "suite")}
+	c.Imports = []string{filepath.Join(dir, "lib")}
+	c.Diagnostics = []string{"-w1"}
+	c.Defines = []string{"DEBUG"}
+
+	// ntt compile does not accept flags of vendor compilers.
+	a, cc := filepath.Join(dir, "suite/a.ttcn3"), filepath.Join(dir, "lib/c.ttcn")
+	assert.Equal(t, []compdb.Command{
+		{Directory: c.Root, File: a, Arguments: []string{"ntt", "compile", a}},
+		{Directory: c.Root, File: cc, Arguments: []string{"ntt", "compile", cc}},
+	}, c.Commands())
+}
+
+func TestDiscoverIndex(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"build/ttcn3_suites.json": `{"suites": [{"root_dir": "../suite"}, {"source_dir": "ignored"}]}`,
+		"suite/a.ttcn3":           `module a {}`,
+	})
+	assert.Equal(t, []Suite{{RootDir: filepath.Join(dir, "suite")}}, Discover(dir))
+}
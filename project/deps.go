@@ -0,0 +1,518 @@
+package project
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nokia/ntt/internal/env"
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/internal/yaml"
+)
+
+// LockFile is the name of the lock file, which records the resolved
+// revisions and content hashes of dependencies.
+const LockFile = "package.lock"
+
+// A Dependency is a TTCN-3 library required by a project. Exactly one of
+// Path, Git or URL must be specified.
+type Dependency struct {
+	// Path is a local directory.
+	Path string `json:"path,omitempty"`
+
+	// Git is the URL of a git repository.
+	Git string `json:"git,omitempty"`
+
+	// Ref is a tag, branch or commit of the git repository. Default:
+	//
+	// 	HEAD
+	Ref string `json:"ref,omitempty"`
+
+	// URL is the URL or path of a tarball (.tar, .tar.gz or .tgz).
+	URL string `json:"url,omitempty"`
+
+	// SHA256 is the expected checksum of the tarball.
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+func (d *Dependency) validate() error {
+	n := 0
+	for _, s := range []string{d.Path, d.Git, d.URL} {
+		if s != "" {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("exactly one of path, git or url required")
+	}
+	return nil
+}
+
+// Lock is the content of the lock file.
+type Lock struct {
+	Dependencies []*LockedDependency `json:"dependencies"`
+}
+
+// A LockedDependency is a dependency resolved to a specific revision.
+type LockedDependency struct {
+	Name       string `json:"name"`
+	Dependency `json:",inline"`
+
+	// Rev is the resolved revision: the commit of a git repository or
+	// the checksum of a tarball.
+	Rev string `json:"rev,omitempty"`
+
+	// Hash is the checksum of the dependency content. Local dependencies
+	// have a hash, too.
+	Hash string `json:"hash,omitempty"`
+}
+
+// ReadLock reads a lock file. A missing lock file results in an empty lock.
+func ReadLock(file string) (*Lock, error) {
+	var l Lock
+	b, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return &l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, &l); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return &l, nil
+}
+
+// Write writes the lock file.
+func (l *Lock) Write(file string) error {
+	sort.Slice(l.Dependencies, func(i, j int) bool {
+		return l.Dependencies[i].Name < l.Dependencies[j].Name
+	})
+	b, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, b, 0644)
+}
+
+// Lookup returns the locked dependency name, or nil if there is none.
+func (l *Lock) Lookup(name string) *LockedDependency {
+	for _, d := range l.Dependencies {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// DepsDir returns the directory dependencies are fetched into. This is the
+// deps directory of NTT_CACHE or, if NTT_CACHE is not set, of the user's cache
+// directory.
+func DepsDir() string {
+	if cache := env.Getenv("NTT_CACHE"); cache != "" {
+		return filepath.Join(filepath.SplitList(cache)[0], "deps")
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "ntt", "deps")
+	}
+	return filepath.Join(os.TempDir(), "ntt", "deps")
+}
+
+// Dir returns the directory of a fetched dependency.
+func (d *LockedDependency) Dir() string {
+	rev := strings.TrimPrefix(d.Rev, "sha256:")
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	return filepath.Join(DepsDir(), d.Name+"@"+rev)
+}
+
+// dependencyDirs returns the import directories of the dependencies. Local
+// dependencies are used directly. Other dependencies are used from the vendor
+// directory or, if not vendored, from the cache directory as recorded in the
+// lock file.
+func (c *Config) dependencyDirs() []string {
+	var (
+		dirs []string
+		lock *Lock
+	)
+	for _, name := range c.dependencyNames() {
+		d := c.Dependencies[name]
+		if d.Path != "" {
+			dirs = append(dirs, fs.Real(c.Root, d.Path))
+			continue
+		}
+		if dir := fs.JoinPath(c.Root, "vendor", name); fs.IsDir(dir) {
+			dirs = append(dirs, dir)
+			continue
+		}
+		if lock == nil {
+			l, err := ReadLock(fs.JoinPath(c.Root, LockFile))
+			if err != nil {
+				log.Verboseln(err.Error())
+				l = &Lock{}
+			}
+			lock = l
+		}
+		if ld := lock.Lookup(name); ld != nil && fs.IsDir(ld.Dir()) {
+			dirs = append(dirs, ld.Dir())
+			continue
+		}
+		log.Verbosef("dependency %q is not fetched. Run ntt deps fetch.\n", name)
+	}
+	return dirs
+}
+
+func (c *Config) dependencyNames() []string {
+	names := make([]string, 0, len(c.Dependencies))
+	for name := range c.Dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FetchDependencies fetches the dependencies of a project into the cache
+// directory and returns the updated lock. Local dependencies are not fetched,
+// but their content is verified like the content of fetched dependencies.
+//
+// Dependencies are fetched at the revision recorded in lock. Dependencies not
+// recorded in lock, whose source changed, or listed in update are resolved
+// again. If update is empty and all is true, all dependencies are resolved
+// again.
+func FetchDependencies(c *Config, lock *Lock, all bool, update ...string) (*Lock, error) {
+	result := &Lock{}
+	for _, name := range c.dependencyNames() {
+		d := c.Dependencies[name]
+		if err := d.validate(); err != nil {
+			return nil, fmt.Errorf("dependency %q: %w", name, err)
+		}
+
+		ld := &LockedDependency{Name: name, Dependency: *d}
+		if old := lock.Lookup(name); old != nil && old.Dependency == *d && !all && !contains(update, name) {
+			ld.Rev = old.Rev
+			ld.Hash = old.Hash
+		}
+
+		var err error
+		switch {
+		case d.Path != "":
+			err = verify(ld, fs.Real(c.Root, d.Path))
+		case d.Git != "":
+			err = fetchGit(c.Root, ld)
+		case d.URL != "":
+			err = fetchTarball(c.Root, ld)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("dependency %q: %w", name, err)
+		}
+		if d.Path == "" {
+			log.Verbosef("fetched %s@%s\n", name, ld.Rev)
+		}
+		result.Dependencies = append(result.Dependencies, ld)
+	}
+	return result, nil
+}
+
+// VendorDependencies copies fetched dependencies into the vendor directory of
+// the project. Local dependencies are not vendored.
+func VendorDependencies(c *Config, lock *Lock) error {
+	for _, ld := range lock.Dependencies {
+		if ld.Path != "" {
+			continue
+		}
+		dst := fs.JoinPath(c.Root, "vendor", ld.Name)
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+		if err := copyDir(ld.Dir(), dst); err != nil {
+			return fmt.Errorf("dependency %q: %w", ld.Name, err)
+		}
+	}
+	return nil
+}
+
+// fetchGit fetches a git dependency. If d.Rev is empty, d.Ref is resolved.
+// Repositories given by relative paths are relative to root.
+func fetchGit(root string, d *LockedDependency) error {
+	repo := d.Git
+	if !strings.Contains(repo, ":") {
+		repo = fs.Real(root, repo)
+	}
+	mirror := filepath.Join(DepsDir(), "git", fs.Slugify(repo))
+	if !fs.IsDir(mirror) {
+		if err := os.MkdirAll(filepath.Dir(mirror), 0755); err != nil {
+			return err
+		}
+		if _, err := git("", "clone", "--quiet", "--mirror", repo, mirror); err != nil {
+			return err
+		}
+	} else if d.Rev == "" || !hasCommit(mirror, d.Rev) {
+		if _, err := git(mirror, "fetch", "--quiet", "--prune", "origin"); err != nil {
+			return err
+		}
+	}
+
+	if d.Rev == "" {
+		ref := d.Ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		rev, err := git(mirror, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+		if err != nil {
+			return fmt.Errorf("unknown ref %q", ref)
+		}
+		d.Rev = rev
+		d.Hash = ""
+	}
+
+	return extract(d, func() (io.Reader, error) {
+		b, err := gitOutput(mirror, "archive", "--format=tar", d.Rev)
+		return bytes.NewReader(b), err
+	})
+}
+
+func hasCommit(mirror string, rev string) bool {
+	_, err := git(mirror, "cat-file", "-e", rev+"^{commit}")
+	return err == nil
+}
+
+// git runs a git command and returns its trimmed output.
+func git(dir string, args ...string) (string, error) {
+	b, err := gitOutput(dir, args...)
+	return strings.TrimSpace(string(b)), err
+}
+
+// gitOutput runs a git command in the repository dir and returns its output.
+func gitOutput(dir string, args ...string) ([]byte, error) {
+	if dir != "" {
+		args = append([]string{"--git-dir", dir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// fetchTarball fetches a tarball dependency. The revision of a tarball is its
+// checksum.
+func fetchTarball(root string, d *LockedDependency) error {
+	b, err := download(root, d.URL)
+	if err != nil {
+		return err
+	}
+	sum := fmt.Sprintf("%x", sha256.Sum256(b))
+	if d.SHA256 != "" && d.SHA256 != sum {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", d.SHA256, sum)
+	}
+	rev := "sha256:" + sum
+	if d.Rev != "" && d.Rev != rev {
+		return fmt.Errorf("checksum mismatch: locked %s, got %s", d.Rev, rev)
+	}
+	d.Rev = rev
+
+	return extract(d, func() (io.Reader, error) {
+		if len(b) > 2 && b[0] == 0x1f && b[1] == 0x8b {
+			return gzip.NewReader(bytes.NewReader(b))
+		}
+		return bytes.NewReader(b), nil
+	})
+}
+
+// download returns the content of a http(s) or file URL, or of a path
+// relative to root.
+func download(root string, s string) ([]byte, error) {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		return os.ReadFile(fs.Real(root, s))
+	}
+	switch u.Scheme {
+	case "file":
+		return os.ReadFile(u.Path)
+	case "http", "https":
+		resp, err := http.Get(s)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s: %s", s, resp.Status)
+		}
+		return io.ReadAll(resp.Body)
+	}
+	return nil, fmt.Errorf("%s: unsupported scheme %q", s, u.Scheme)
+}
+
+// extract extracts the tar archive provided by open into the directory of d,
+// unless the directory exists already. The content hash is verified like
+// verify does.
+func extract(d *LockedDependency, open func() (io.Reader, error)) error {
+	dir := d.Dir()
+	if !fs.IsDir(dir) {
+		r, err := open()
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(DepsDir(), 0755); err != nil {
+			return err
+		}
+		tmp, err := os.MkdirTemp(DepsDir(), ".tmp-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		if err := untar(r, tmp); err != nil {
+			return err
+		}
+		if err := os.Rename(stripTopDir(tmp), dir); err != nil {
+			return err
+		}
+	}
+
+	return verify(d, dir)
+}
+
+// verify verifies the content hash of directory dir against d.Hash, or
+// records it if d.Hash is empty.
+func verify(d *LockedDependency, dir string) error {
+	hash, err := hashDir(dir)
+	if err != nil {
+		return err
+	}
+	if d.Hash != "" && d.Hash != hash {
+		return fmt.Errorf("%s: content hash mismatch: locked %s, got %s", dir, d.Hash, hash)
+	}
+	d.Hash = hash
+	return nil
+}
+
+func untar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path in archive: %s", hdr.Name)
+		}
+		path := filepath.Join(dir, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode).Perm()|0600)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// stripTopDir returns the only directory of dir, if dir contains nothing else.
+// Tarballs usually contain a single top level directory.
+func stripTopDir(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name())
+	}
+	return dir
+}
+
+// hashDir returns a checksum over the relative paths and contents of all
+// regular files in dir.
+func hashDir(dir string) (string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	w := bufio.NewWriter(h)
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		rel, _ := filepath.Rel(dir, file)
+		fmt.Fprintf(w, "%x  %s\n", sha256.Sum256(b), filepath.ToSlash(rel))
+	}
+	w.Flush()
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, 0755)
+		case info.Mode().IsRegular():
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(target, b, info.Mode().Perm())
+		}
+		return nil
+	})
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package project
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gitRepo creates a bare git repository with a commit for each of the given
+// file sets and tags the commits v1, v2, ...
+func gitRepo(t *testing.T, dir string, commits ...map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	bare := filepath.Join(dir, "lib.git")
+	work := filepath.Join(dir, "work")
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	run(dir, "init", "--quiet", "--bare", bare)
+	run(dir, "clone", "--quiet", bare, work)
+	for i, files := range commits {
+		writeFiles(t, work, files)
+		run(work, "add", "-A")
+		run(work, "commit", "--quiet", "-m", "commit")
+		run(work, "tag", "v"+string(rune('1'+i)))
+	}
+	run(work, "push", "--quiet", "--tags", "origin", "HEAD:master")
+	return bare
+}
+
+func TestFetchGitDependency(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NTT_CACHE", filepath.Join(dir, "cache"))
+	gitRepo(t, dir,
+		map[string]string{"lib.ttcn3": "module lib { const integer x := 1 }"},
+		map[string]string{"lib.ttcn3": "module lib { const integer x := 2 }"},
+	)
+	writeFiles(t, dir, map[string]string{
+		"suite/package.yml": "dependencies:\n  lib:\n    git: ../lib.git\n    ref: v1\n",
+	})
+	manifest := filepath.Join(dir, "suite", ManifestFile)
+	lockFile := filepath.Join(dir, "suite", LockFile)
+
+	c, err := NewConfig(WithManifest(manifest))
+	assert.Nil(t, err)
+	assert.Empty(t, c.Imports, "dependency must not be used before it is fetched")
+
+	// Fetch v1 and record it in the lock file.
+	lock, err := FetchDependencies(c, &Lock{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, lock.Write(lockFile))
+	v1 := lock.Lookup("lib")
+	assert.Equal(t, 40, len(v1.Rev))
+	assert.Regexp(t, "^sha256:", v1.Hash)
+
+	c, err = NewConfig(WithManifest(manifest))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{v1.Dir()}, c.Imports)
+	b, _ := os.ReadFile(filepath.Join(v1.Dir(), "lib.ttcn3"))
+	assert.Equal(t, "module lib { const integer x := 1 }", string(b))
+
+	// The locked revision is kept, even if the ref moves.
+	c.Dependencies["lib"].Ref = "v1"
+	lock, err = FetchDependencies(c, lock, false)
+	assert.Nil(t, err)
+	assert.Equal(t, v1.Rev, lock.Lookup("lib").Rev)
+
+	// Tampered content is detected.
+	os.WriteFile(filepath.Join(v1.Dir(), "lib.ttcn3"), []byte("module lib {}"), 0644)
+	_, err = FetchDependencies(c, lock, false)
+	assert.ErrorContains(t, err, "content hash mismatch")
+	os.RemoveAll(v1.Dir())
+
+	// Changing the ref resolves the dependency again.
+	c.Dependencies["lib"].Ref = "v2"
+	lock, err = FetchDependencies(c, lock, false)
+	assert.Nil(t, err)
+	assert.NotEqual(t, v1.Rev, lock.Lookup("lib").Rev)
+
+	// Unknown refs are reported.
+	c.Dependencies["lib"].Ref = "v3"
+	_, err = FetchDependencies(c, lock, true)
+	assert.ErrorContains(t, err, `unknown ref "v3"`)
+
+	// Vendored dependencies take precedence.
+	c.Dependencies["lib"].Ref = "v2"
+	assert.Nil(t, VendorDependencies(c, lock))
+	assert.Nil(t, lock.Write(lockFile))
+	c, err = NewConfig(WithManifest(manifest))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "suite", "vendor", "lib")}, c.Imports)
+}
+
+func TestFetchTarballDependency(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NTT_CACHE", filepath.Join(dir, "cache"))
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	content := []byte("module codec {}")
+	tw.WriteHeader(&tar.Header{Name: "codec-1.0/", Typeflag: tar.TypeDir, Mode: 0755})
+	tw.WriteHeader(&tar.Header{Name: "codec-1.0/codec.ttcn3", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))})
+	tw.Write(content)
+	tw.Close()
+	zw.Close()
+
+	writeFiles(t, dir, map[string]string{
+		"codec-1.0.tar.gz":    buf.String(),
+		"suite/package.yml":   "dependencies:\n  codec:\n    url: ../codec-1.0.tar.gz\n  common:\n    path: ../common\n",
+		"common/common.ttcn3": "module common {}",
+	})
+
+	c, err := NewConfig(WithManifest(filepath.Join(dir, "suite", ManifestFile)))
+	assert.Nil(t, err)
+	lock, err := FetchDependencies(c, &Lock{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codec := lock.Lookup("codec")
+	assert.Regexp(t, "^sha256:", codec.Rev)
+	b, _ := os.ReadFile(filepath.Join(codec.Dir(), "codec.ttcn3"))
+	assert.Equal(t, string(content), string(b))
+	assert.Equal(t, "", lock.Lookup("common").Rev)
+	assert.Regexp(t, "^sha256:", lock.Lookup("common").Hash)
+
+	// Changes of local dependencies are detected, until they are updated.
+	writeFiles(t, dir, map[string]string{"common/common.ttcn3": "module common { const integer x := 1 }"})
+	_, err = FetchDependencies(c, lock, false)
+	assert.ErrorContains(t, err, "content hash mismatch")
+	lock, err = FetchDependencies(c, lock, false, "common")
+	assert.Nil(t, err)
+
+	c.Dependencies["codec"].SHA256 = "0000"
+	_, err = FetchDependencies(c, lock, false)
+	assert.ErrorContains(t, err, "checksum mismatch")
+}
@@ -0,0 +1,172 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nokia/ntt/internal/yaml"
+)
+
+// An Origin is a source of a configuration value, like a manifest file, an
+// environment variable or a default.
+type Origin struct {
+	// Source describes where the value comes from.
+	Source string `json:"source"`
+
+	// Value is the raw value as provided by the source, before variable
+	// expansion.
+	Value string `json:"value"`
+}
+
+// An Explanation describes how the value of a configuration key or variable
+// was resolved.
+type Explanation struct {
+	// Key is a configuration key (e.g. "sources") or a variable
+	// (e.g. "variables.CFLAGS").
+	Key string `json:"key"`
+
+	// Value is the resolved value.
+	Value string `json:"value"`
+
+	// Origin is the source providing the resolved value.
+	Origin Origin `json:"origin"`
+
+	// Overridden lists the sources overridden by Origin, the most recent
+	// first.
+	Overridden []Origin `json:"overridden,omitempty"`
+}
+
+// record records source as origin of the configuration key. Sources recorded
+// later override sources recorded earlier.
+func (c *Config) record(key string, source string, v interface{}) {
+	c.recordBefore(key, source, v, "")
+}
+
+// recordBefore records an origin of key like record does, but inserts it
+// before the first origin from source before. Recording before a source is
+// required for files, which are merged before source, but are read after it.
+func (c *Config) recordBefore(key string, source string, v interface{}, before string) {
+	o := Origin{Source: source, Value: formatValue(v)}
+	if c.origins == nil {
+		c.origins = make(map[string][]Origin)
+	}
+	// Options might be applied repeatedly. Keep only the most recent
+	// record of a source.
+	var (
+		list     []Origin
+		inserted bool
+	)
+	for _, x := range c.origins[key] {
+		if x == o {
+			continue
+		}
+		if !inserted && before != "" && x.Source == before {
+			list = append(list, o)
+			inserted = true
+		}
+		list = append(list, x)
+	}
+	if !inserted {
+		list = append(list, o)
+	}
+	c.origins[key] = list
+}
+
+// recordManifest records all keys set by a manifest.
+func (c *Config) recordManifest(file string) {
+	v := reflect.ValueOf(c.Manifest)
+	for key, fv := range structValues(v) {
+		if key == "variables" || key == "include" || fv.IsZero() {
+			continue
+		}
+		c.record(key, file, fv.Interface())
+	}
+}
+
+// Explain returns the resolved values of the configuration and its variables
+// along with their origins, sorted by key.
+func (c *Config) Explain() []Explanation {
+	var conf map[string]interface{}
+	if b, err := yaml.MarshalJSON(c); err == nil {
+		json.Unmarshal(b, &conf)
+	}
+
+	var list []Explanation
+	for key, origins := range c.origins {
+		e := Explanation{Key: key, Origin: origins[len(origins)-1]}
+		for i := len(origins) - 2; i >= 0; i-- {
+			e.Overridden = append(e.Overridden, origins[i])
+		}
+		if name := strings.TrimPrefix(key, "variables."); name != key {
+			e.Value = c.Variables[name]
+		} else {
+			e.Value = formatValue(conf[key])
+		}
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+// structValues returns the fields of a struct value keyed by their YAML keys.
+// Inlined structs are flattened.
+func structValues(v reflect.Value) map[string]reflect.Value {
+	ret := make(map[string]reflect.Value)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, inline := fieldName(f)
+		if inline {
+			for k, fv := range structValues(v.Field(i)) {
+				ret[k] = fv
+			}
+			continue
+		}
+		ret[name] = v.Field(i)
+	}
+	return ret
+}
+
+// formatValue formats a configuration value. Lists of strings are separated
+// by blanks. Complex values are formatted as JSON.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, " ")
+	case []interface{}:
+		s := make([]string, len(v))
+		for i, x := range v {
+			switch x.(type) {
+			case []interface{}, map[string]interface{}:
+				b, _ := json.Marshal(v)
+				return string(b)
+			}
+			s[i] = formatValue(x)
+		}
+		return strings.Join(s, " ")
+	case yaml.Duration:
+		b, _ := v.MarshalText()
+		return string(b)
+	case fmt.Stringer:
+		return v.String()
+	case float64, int, bool:
+		return fmt.Sprint(v)
+	}
+	b, err := yaml.MarshalJSON(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(b))
+}
@@ -0,0 +1,89 @@
+package project
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NTT_CACHE", dir)
+	t.Setenv("NTT_TIMEOUT", "7")
+	t.Setenv("NTT_FOO", "env")
+	writeFiles(t, dir, map[string]string{
+		"package.yml":      "name: suite\nsources: [${SRC}/a.ttcn3]\ntimeout: 5\nvariables:\n  SRC: src\n  BAR: manifest\n",
+		"ntt.env":          "BAR=envfile\n",
+		"suite.parameters": "presets:\n  fast: {timeout: 1}\n",
+	})
+
+	c, err := NewConfig(WithManifest(filepath.Join(dir, ManifestFile)), AutomaticEnv(), WithDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make(map[string]Explanation)
+	for _, e := range c.Explain() {
+		actual[e.Key] = e
+	}
+
+	manifest := filepath.Join(dir, ManifestFile)
+	assert.Equal(t, Explanation{
+		Key:    "sources",
+		Value:  filepath.Join(dir, "src", "a.ttcn3"),
+		Origin: Origin{Source: manifest, Value: "${SRC}/a.ttcn3"},
+	}, actual["sources"])
+	assert.Equal(t, Explanation{
+		Key:        "timeout",
+		Value:      "7",
+		Origin:     Origin{Source: "environment variable NTT_TIMEOUT", Value: "7"},
+		Overridden: []Origin{{Source: manifest, Value: "5"}},
+	}, actual["timeout"])
+	assert.Equal(t, Explanation{
+		Key:        "variables.BAR",
+		Value:      "envfile",
+		Origin:     Origin{Source: filepath.Join(dir, "ntt.env"), Value: "envfile"},
+		Overridden: []Origin{{Source: manifest, Value: "manifest"}},
+	}, actual["variables.BAR"])
+	assert.Equal(t, "environment variable NTT_FOO", actual["variables.NTT_FOO"].Origin.Source)
+	assert.Equal(t, "default", actual["parameters_file"].Origin.Source)
+	assert.Equal(t, filepath.Join(dir, "suite.parameters"), actual["presets"].Origin.Source)
+	assert.Equal(t, "default (root)", actual["source_dir"].Origin.Source)
+}
+
+func TestExplainInclude(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NTT_CACHE", dir)
+	t.Setenv("NTT_TIMEOUT", "7")
+	writeFiles(t, dir, map[string]string{
+		"package.yml":       "name: suite\ninclude: [common.parameters]\ntimeout: 5\n",
+		"common.parameters": "timeout: 3\nparameters:\n  A.x: \"1\"\n",
+	})
+
+	manifest := filepath.Join(dir, ManifestFile)
+	common := filepath.Join(dir, "common.parameters")
+	c, err := NewConfig(WithManifest(manifest), AutomaticEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make(map[string]Explanation)
+	for _, e := range c.Explain() {
+		actual[e.Key] = e
+	}
+
+	// Included files are merged before the manifest.
+	assert.Equal(t, Explanation{
+		Key:        "timeout",
+		Value:      "7",
+		Origin:     Origin{Source: "environment variable NTT_TIMEOUT", Value: "7"},
+		Overridden: []Origin{{Source: manifest, Value: "5"}, {Source: common, Value: "3"}},
+	}, actual["timeout"])
+	assert.Equal(t, Explanation{
+		Key:    "parameters",
+		Value:  `{"A.x": "1"}`,
+		Origin: Origin{Source: common, Value: `{"A.x": "1"}`},
+	}, actual["parameters"])
+	assert.NotContains(t, actual, "include")
+}
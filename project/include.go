@@ -0,0 +1,179 @@
+package project
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/nokia/ntt/internal/env"
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/yaml"
+)
+
+// A Conflict describes a module parameter assigned different values by two
+// parameters files, where neither file includes the other.
+type Conflict struct {
+	// Preset is the name of the preset assigning the parameter or empty
+	// for the global configuration.
+	Preset string `json:"preset,omitempty"`
+
+	// Name is the name of the module parameter.
+	Name string `json:"name"`
+
+	// Files are the conflicting files in merge order. The value from the
+	// last file is used.
+	Files [2]string `json:"files"`
+
+	// Values are the values assigned by Files.
+	Values [2]string `json:"values"`
+}
+
+func (c Conflict) String() string {
+	name := c.Name
+	if c.Preset != "" {
+		name = fmt.Sprintf("%s (preset %s)", c.Name, c.Preset)
+	}
+	return fmt.Sprintf("%s: %s = %q conflicts with %s = %q", c.Files[1], name, c.Values[1], c.Files[0], c.Values[0])
+}
+
+// paramsFile is a parameters file, with its includes already resolved.
+type paramsFile struct {
+	file   string
+	params Parameters
+}
+
+// includer resolves the include directives of parameters files.
+//
+// Included files are merged before the including file, in the order they
+// are listed. Files included more than once are merged only once, at their
+// first occurrence.
+type includer struct {
+	vars env.Env
+
+	// files is the list of resolved files in merge order.
+	files []paramsFile
+
+	// active are the files currently being resolved. It is used to detect
+	// include cycles.
+	active map[string]bool
+
+	// deps maps files to the files they include, directly or indirectly.
+	deps map[string]map[string]bool
+
+	// assigned maps module parameters to the file assigning them last.
+	assigned  map[paramKey]paramsValue
+	conflicts []Conflict
+}
+
+type paramKey struct{ preset, name string }
+
+type paramsValue struct{ file, value string }
+
+// add resolves the includes of file and appends the included files and file
+// itself to the list of files. p is the content of file.
+func (l *includer) add(file string, p Parameters) error {
+	if l.active == nil {
+		l.active = make(map[string]bool)
+		l.deps = make(map[string]map[string]bool)
+		l.assigned = make(map[paramKey]paramsValue)
+	}
+
+	l.active[file] = true
+	defer delete(l.active, file)
+
+	deps := make(map[string]bool)
+	for _, inc := range p.Include {
+		s, err := env.Expand(inc, l.copyVars())
+		if err != nil {
+			return fmt.Errorf("%s: include %s: %w", file, inc, err)
+		}
+		path := fs.Real(filepath.Dir(file), s)
+		if l.active[path] {
+			return fmt.Errorf("%s: include cycle: %s", file, path)
+		}
+		if _, ok := l.deps[path]; !ok {
+			b, err := fs.Content(path)
+			if err != nil {
+				return fmt.Errorf("%s: include: %w", file, err)
+			}
+			var pf Parameters
+			if err := yaml.Unmarshal(b, &pf); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			if err := l.add(path, pf); err != nil {
+				return err
+			}
+		}
+		deps[path] = true
+		for d := range l.deps[path] {
+			deps[d] = true
+		}
+	}
+	l.deps[file] = deps
+
+	l.check(file, "", p.Parameters)
+	for _, name := range sortedKeys(p.Presets) {
+		l.check(file, name, p.Presets[name].Parameters)
+	}
+
+	p.Include = nil
+	l.files = append(l.files, paramsFile{file: file, params: p})
+	return nil
+}
+
+// check records the module parameters assigned by file and reports
+// conflicting assignments of files not included by file.
+func (l *includer) check(file string, preset string, params map[string]string) {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		k := paramKey{preset: preset, name: name}
+		v := params[name]
+		if prev, ok := l.assigned[k]; ok && prev.value != v && prev.file != file && !l.deps[file][prev.file] {
+			l.conflicts = append(l.conflicts, Conflict{
+				Preset: preset,
+				Name:   name,
+				Files:  [2]string{prev.file, file},
+				Values: [2]string{prev.value, v},
+			})
+		}
+		l.assigned[k] = paramsValue{file: file, value: v}
+	}
+}
+
+// merge returns the merged parameters of all files.
+func (l *includer) merge() Parameters {
+	var p Parameters
+	for _, f := range l.files {
+		p = mergeParameters(p, f.params)
+	}
+	return p
+}
+
+func (l *includer) copyVars() env.Env {
+	vars := make(env.Env, len(l.vars))
+	for k, v := range l.vars {
+		vars[k] = v
+	}
+	return vars
+}
+
+func sortedKeys(m map[string]TestConfig) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Conflicts returns the module parameters assigned differently by the
+// manifest and the parameters files. Files overriding values of files they
+// include do not conflict.
+func (c *Config) Conflicts() []Conflict {
+	return c.conflicts
+}
@@ -0,0 +1,62 @@
+package project
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInclude(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NTT_CACHE", dir)
+	writeFiles(t, dir, map[string]string{
+		"package.yml":            "name: suite\ninclude: [common/base.parameters]\nparameters: {M.A: manifest}\n",
+		"suite.parameters":       "include: [common/a.parameters, common/b.parameters]\nparameters: {M.C: suite}\n",
+		"common/base.parameters": "timeout: 5\nparameters: {M.A: base, M.B: base}\n",
+		"common/a.parameters":    "include: [base.parameters]\nparameters: {M.B: a}\npresets:\n  fast: {parameters: {M.D: a}}\nexecute: [{test: M.TC1}]\n",
+		"common/b.parameters":    "parameters: {M.B: b}\npresets:\n  fast: {parameters: {M.D: b}}\nexecute: [{test: M.TC2}]\n",
+	})
+
+	c, err := NewConfig(WithManifest(filepath.Join(dir, ManifestFile)), WithDefaults())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, c.Include)
+	assert.Equal(t, 5.0, c.Timeout.Seconds())
+	assert.Equal(t, map[string]string{"M.A": "manifest", "M.B": "b", "M.C": "suite"}, c.Parameters.Parameters)
+	assert.Equal(t, map[string]string{"M.D": "b"}, c.Presets["fast"].Parameters)
+	assert.Equal(t, []TestConfig{{Test: "M.TC1"}, {Test: "M.TC2"}}, c.Execute)
+
+	a := filepath.Join(dir, "common", "a.parameters")
+	b := filepath.Join(dir, "common", "b.parameters")
+	assert.Equal(t, []Conflict{
+		{Name: "M.B", Files: [2]string{a, b}, Values: [2]string{"a", "b"}},
+		{Preset: "fast", Name: "M.D", Files: [2]string{a, b}, Values: [2]string{"a", "b"}},
+	}, c.Conflicts())
+
+	problems, err := CheckManifest(filepath.Join(dir, ManifestFile))
+	assert.Nil(t, err)
+	var actual []string
+	for _, p := range problems {
+		actual = append(actual, p.String())
+	}
+	assert.Equal(t, []string{
+		b + `:1:19: module parameter M.B: "b" conflicts with "a" from ` + a,
+		b + `:3:28: presets.fast: module parameter M.D: "b" conflicts with "a" from ` + a,
+	}, actual)
+}
+
+func TestIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("NTT_CACHE", dir)
+	writeFiles(t, dir, map[string]string{
+		"package.yml":      "name: suite\n",
+		"suite.parameters": "include: [a.parameters]\n",
+		"a.parameters":     "include: [suite.parameters]\n",
+	})
+
+	_, err := NewConfig(WithManifest(filepath.Join(dir, ManifestFile)), WithDefaults())
+	assert.ErrorContains(t, err, "include cycle")
+}
@@ -1 +1,19 @@
 package titan
+
+// ProjectResources holds the resource properties of build configurations.
+// The generated ConfigurationType cannot decode them, because the lists of
+// FolderProperties and FileProperties share their element names with the
+// properties of single resources.
+type ProjectResources struct {
+	Configurations struct {
+		Configuration []struct {
+			NameAttr         string `xml:"name,attr"`
+			FolderProperties struct {
+				FolderResource []*FolderResource `xml:"FolderResource"`
+			} `xml:"FolderProperties"`
+			FileProperties struct {
+				FileResource []*FileResource `xml:"FileResource"`
+			} `xml:"FileProperties"`
+		} `xml:"Configuration"`
+	} `xml:"Configurations"`
+}
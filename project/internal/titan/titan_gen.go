@@ -7,65 +7,65 @@ import (
 type TITANProjectFileInformation *TopLevelProjectType
 
 type TTCN3preprocessorDefines struct {
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type TTCN3preprocessorUndefines struct {
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type PreprocessorDefines struct {
 	XMLName  xml.Name `xml:"preprocessorDefines"`
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type PreprocessorUndefines struct {
 	XMLName  xml.Name `xml:"preprocessorUndefines"`
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type TTCN3preprocessorIncludes struct {
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type PreprocessorIncludes struct {
 	XMLName  xml.Name `xml:"preprocessorIncludes"`
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type SolarisSpecificLibraries struct {
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type Solaris8SpecificLibraries struct {
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type FreeBSDSpecificLibraries struct {
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type LinuxSpecificLibraries struct {
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type Win32SpecificLibraries struct {
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type AdditionalObjects struct {
 	XMLName  xml.Name `xml:"additionalObjects"`
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type LinkerLibraries struct {
 	XMLName  xml.Name `xml:"linkerLibraries"`
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type LinkerLibrarySearchPath struct {
 	XMLName  xml.Name `xml:"linkerLibrarySearchPath"`
-	ListItem []string `xml:"listItem"`
+	ListItem []string `xml:"listItem,omitempty"`
 }
 
 type Target struct {
@@ -74,164 +74,164 @@ type Target struct {
 }
 
 type Targets struct {
-	Target []*Target `xml:"Target"`
+	Target []*Target `xml:"Target,omitempty"`
 }
 
 type ProjectSpecificRulesGenerator struct {
-	GeneratorCommand string   `xml:"GeneratorCommand"`
-	Targets          *Targets `xml:"Targets"`
+	GeneratorCommand string   `xml:"GeneratorCommand,omitempty"`
+	Targets          *Targets `xml:"Targets,omitempty"`
 }
 
 type MakefileSettings struct {
-	GenerateMakefile                bool                           `xml:"generateMakefile"`
-	GenerateInternalMakefile        bool                           `xml:"generateInternalMakefile"`
-	SymboliclinklessBuild           bool                           `xml:"symboliclinklessBuild"`
-	UseAbsolutePath                 bool                           `xml:"useAbsolutePath"`
-	GNUMake                         bool                           `xml:"GNUMake"`
-	IncrementalDependencyRefresh    bool                           `xml:"incrementalDependencyRefresh"`
-	DynamicLinking                  bool                           `xml:"dynamicLinking"`
-	FunctiontestRuntime             bool                           `xml:"functiontestRuntime"`
-	SingleMode                      bool                           `xml:"singleMode"`
-	CodeSplitting                   int                            `xml:"codeSplitting"`
-	DefaultTarget                   string                         `xml:"defaultTarget"`
-	TargetExecutable                string                         `xml:"targetExecutable"`
-	TTCN3preprocessor               string                         `xml:"TTCN3preprocessor"`
-	TTCN3preprocessorDefines        *TTCN3preprocessorDefines      `xml:"TTCN3preprocessorDefines"`
-	TTCN3preprocessorUndefines      *TTCN3preprocessorUndefines    `xml:"TTCN3preprocessorUndefines"`
-	PreprocessorDefines             *PreprocessorDefines           `xml:"preprocessorDefines"`
-	PreprocessorUndefines           *PreprocessorUndefines         `xml:"preprocessorUndefines"`
-	TTCN3preprocessorIncludes       *TTCN3preprocessorIncludes     `xml:"TTCN3preprocessorIncludes"`
-	PreprocessorIncludes            *PreprocessorIncludes          `xml:"preprocessorIncludes"`
-	SemanticCheckOnly               bool                           `xml:"semanticCheckOnly"`
-	DisableAttributeValidation      bool                           `xml:"disableAttributeValidation"`
-	DisableBER                      bool                           `xml:"disableBER"`
-	DisableRAW                      bool                           `xml:"disableRAW"`
-	DisableTEXT                     bool                           `xml:"disableTEXT"`
-	DisableXER                      bool                           `xml:"disableXER"`
-	DisableJSON                     bool                           `xml:"disableJSON"`
-	DisableOER                      bool                           `xml:"disableOER"`
-	ForceXERinASN1                  bool                           `xml:"forceXERinASN.1"`
-	DefaultasOmit                   bool                           `xml:"defaultasOmit"`
-	EnumHackProperty                bool                           `xml:"enumHackProperty"`
-	ForceOldFuncOutParHandling      bool                           `xml:"forceOldFuncOutParHandling"`
-	GccMessageFormat                bool                           `xml:"gccMessageFormat"`
-	LineNumbersOnlyInMessages       bool                           `xml:"lineNumbersOnlyInMessages"`
-	IncludeSourceInfo               bool                           `xml:"includeSourceInfo"`
-	AddSourceLineInfo               bool                           `xml:"addSourceLineInfo"`
-	SuppressWarnings                bool                           `xml:"suppressWarnings"`
-	OutParamBoundness               bool                           `xml:"outParamBoundness"`
-	OmitInValueList                 bool                           `xml:"omitInValueList"`
-	WarningsForBadVariants          bool                           `xml:"warningsForBadVariants"`
-	IgnoreUntaggedOnTopLevelUnion   bool                           `xml:"ignoreUntaggedOnTopLevelUnion"`
-	ActivateDebugger                bool                           `xml:"activateDebugger"`
-	Quietly                         bool                           `xml:"quietly"`
-	EnableLegacyEncoding            bool                           `xml:"enableLegacyEncoding"`
-	DisableUserInformation          bool                           `xml:"disableUserInformation"`
-	EnableRealtimeTesting           bool                           `xml:"enableRealtimeTesting"`
-	NamingRules                     string                         `xml:"namingRules"`
-	DisableSubtypeChecking          bool                           `xml:"disableSubtypeChecking"`
-	ForceGenSeof                    bool                           `xml:"forceGenSeof"`
-	EnableOOP                       bool                           `xml:"enableOOP"`
-	CharstringCompat                bool                           `xml:"charstringCompat"`
-	CxxCompiler                     string                         `xml:"CxxCompiler"`
-	OptimizationLevel               string                         `xml:"optimizationLevel"`
-	OtherOptimizationFlags          string                         `xml:"otherOptimizationFlags"`
-	ProfiledFileList                *ResourceType                  `xml:"profiledFileList"`
-	SolarisSpecificLibraries        *SolarisSpecificLibraries      `xml:"SolarisSpecificLibraries"`
-	Solaris8SpecificLibraries       *Solaris8SpecificLibraries     `xml:"Solaris8SpecificLibraries"`
-	FreeBSDSpecificLibraries        *FreeBSDSpecificLibraries      `xml:"FreeBSDSpecificLibraries"`
-	LinuxSpecificLibraries          *LinuxSpecificLibraries        `xml:"LinuxSpecificLibraries"`
-	Win32SpecificLibraries          *Win32SpecificLibraries        `xml:"Win32SpecificLibraries"`
-	AdditionalObjects               *AdditionalObjects             `xml:"additionalObjects"`
-	LinkerLibraries                 *LinkerLibraries               `xml:"linkerLibraries"`
-	LinkerLibrarySearchPath         *LinkerLibrarySearchPath       `xml:"linkerLibrarySearchPath"`
-	DisablePredefinedExternalFolder bool                           `xml:"disablePredefinedExternalFolder"`
-	UseGoldLinker                   bool                           `xml:"useGoldLinker"`
-	FreeTextLinkerOptions           string                         `xml:"freeTextLinkerOptions"`
-	BuildLevel                      string                         `xml:"buildLevel"`
-	ProjectSpecificRulesGenerator   *ProjectSpecificRulesGenerator `xml:"ProjectSpecificRulesGenerator"`
+	GenerateMakefile                bool                           `xml:"generateMakefile,omitempty"`
+	GenerateInternalMakefile        bool                           `xml:"generateInternalMakefile,omitempty"`
+	SymboliclinklessBuild           bool                           `xml:"symboliclinklessBuild,omitempty"`
+	UseAbsolutePath                 bool                           `xml:"useAbsolutePath,omitempty"`
+	GNUMake                         bool                           `xml:"GNUMake,omitempty"`
+	IncrementalDependencyRefresh    bool                           `xml:"incrementalDependencyRefresh,omitempty"`
+	DynamicLinking                  bool                           `xml:"dynamicLinking,omitempty"`
+	FunctiontestRuntime             bool                           `xml:"functiontestRuntime,omitempty"`
+	SingleMode                      bool                           `xml:"singleMode,omitempty"`
+	CodeSplitting                   int                            `xml:"codeSplitting,omitempty"`
+	DefaultTarget                   string                         `xml:"defaultTarget,omitempty"`
+	TargetExecutable                string                         `xml:"targetExecutable,omitempty"`
+	TTCN3preprocessor               string                         `xml:"TTCN3preprocessor,omitempty"`
+	TTCN3preprocessorDefines        *TTCN3preprocessorDefines      `xml:"TTCN3preprocessorDefines,omitempty"`
+	TTCN3preprocessorUndefines      *TTCN3preprocessorUndefines    `xml:"TTCN3preprocessorUndefines,omitempty"`
+	PreprocessorDefines             *PreprocessorDefines           `xml:"preprocessorDefines,omitempty"`
+	PreprocessorUndefines           *PreprocessorUndefines         `xml:"preprocessorUndefines,omitempty"`
+	TTCN3preprocessorIncludes       *TTCN3preprocessorIncludes     `xml:"TTCN3preprocessorIncludes,omitempty"`
+	PreprocessorIncludes            *PreprocessorIncludes          `xml:"preprocessorIncludes,omitempty"`
+	SemanticCheckOnly               bool                           `xml:"semanticCheckOnly,omitempty"`
+	DisableAttributeValidation      bool                           `xml:"disableAttributeValidation,omitempty"`
+	DisableBER                      bool                           `xml:"disableBER,omitempty"`
+	DisableRAW                      bool                           `xml:"disableRAW,omitempty"`
+	DisableTEXT                     bool                           `xml:"disableTEXT,omitempty"`
+	DisableXER                      bool                           `xml:"disableXER,omitempty"`
+	DisableJSON                     bool                           `xml:"disableJSON,omitempty"`
+	DisableOER                      bool                           `xml:"disableOER,omitempty"`
+	ForceXERinASN1                  bool                           `xml:"forceXERinASN.1,omitempty"`
+	DefaultasOmit                   bool                           `xml:"defaultasOmit,omitempty"`
+	EnumHackProperty                bool                           `xml:"enumHackProperty,omitempty"`
+	ForceOldFuncOutParHandling      bool                           `xml:"forceOldFuncOutParHandling,omitempty"`
+	GccMessageFormat                bool                           `xml:"gccMessageFormat,omitempty"`
+	LineNumbersOnlyInMessages       bool                           `xml:"lineNumbersOnlyInMessages,omitempty"`
+	IncludeSourceInfo               bool                           `xml:"includeSourceInfo,omitempty"`
+	AddSourceLineInfo               bool                           `xml:"addSourceLineInfo,omitempty"`
+	SuppressWarnings                bool                           `xml:"suppressWarnings,omitempty"`
+	OutParamBoundness               bool                           `xml:"outParamBoundness,omitempty"`
+	OmitInValueList                 bool                           `xml:"omitInValueList,omitempty"`
+	WarningsForBadVariants          bool                           `xml:"warningsForBadVariants,omitempty"`
+	IgnoreUntaggedOnTopLevelUnion   bool                           `xml:"ignoreUntaggedOnTopLevelUnion,omitempty"`
+	ActivateDebugger                bool                           `xml:"activateDebugger,omitempty"`
+	Quietly                         bool                           `xml:"quietly,omitempty"`
+	EnableLegacyEncoding            bool                           `xml:"enableLegacyEncoding,omitempty"`
+	DisableUserInformation          bool                           `xml:"disableUserInformation,omitempty"`
+	EnableRealtimeTesting           bool                           `xml:"enableRealtimeTesting,omitempty"`
+	NamingRules                     string                         `xml:"namingRules,omitempty"`
+	DisableSubtypeChecking          bool                           `xml:"disableSubtypeChecking,omitempty"`
+	ForceGenSeof                    bool                           `xml:"forceGenSeof,omitempty"`
+	EnableOOP                       bool                           `xml:"enableOOP,omitempty"`
+	CharstringCompat                bool                           `xml:"charstringCompat,omitempty"`
+	CxxCompiler                     string                         `xml:"CxxCompiler,omitempty"`
+	OptimizationLevel               string                         `xml:"optimizationLevel,omitempty"`
+	OtherOptimizationFlags          string                         `xml:"otherOptimizationFlags,omitempty"`
+	ProfiledFileList                *ResourceType                  `xml:"profiledFileList,omitempty"`
+	SolarisSpecificLibraries        *SolarisSpecificLibraries      `xml:"SolarisSpecificLibraries,omitempty"`
+	Solaris8SpecificLibraries       *Solaris8SpecificLibraries     `xml:"Solaris8SpecificLibraries,omitempty"`
+	FreeBSDSpecificLibraries        *FreeBSDSpecificLibraries      `xml:"FreeBSDSpecificLibraries,omitempty"`
+	LinuxSpecificLibraries          *LinuxSpecificLibraries        `xml:"LinuxSpecificLibraries,omitempty"`
+	Win32SpecificLibraries          *Win32SpecificLibraries        `xml:"Win32SpecificLibraries,omitempty"`
+	AdditionalObjects               *AdditionalObjects             `xml:"additionalObjects,omitempty"`
+	LinkerLibraries                 *LinkerLibraries               `xml:"linkerLibraries,omitempty"`
+	LinkerLibrarySearchPath         *LinkerLibrarySearchPath       `xml:"linkerLibrarySearchPath,omitempty"`
+	DisablePredefinedExternalFolder bool                           `xml:"disablePredefinedExternalFolder,omitempty"`
+	UseGoldLinker                   bool                           `xml:"useGoldLinker,omitempty"`
+	FreeTextLinkerOptions           string                         `xml:"freeTextLinkerOptions,omitempty"`
+	BuildLevel                      string                         `xml:"buildLevel,omitempty"`
+	ProjectSpecificRulesGenerator   *ProjectSpecificRulesGenerator `xml:"ProjectSpecificRulesGenerator,omitempty"`
 }
 
 type LocalBuildSettings struct {
-	MakefileFlags    string `xml:"MakefileFlags"`
-	MakefileScript   string `xml:"MakefileScript"`
-	WorkingDirectory string `xml:"workingDirectory"`
+	MakefileFlags    string `xml:"MakefileFlags,omitempty"`
+	MakefileScript   string `xml:"MakefileScript,omitempty"`
+	WorkingDirectory string `xml:"workingDirectory,omitempty"`
 }
 
 type RemoteHost struct {
-	Active  bool   `xml:"Active"`
-	Name    string `xml:"Name"`
-	Command string `xml:"Command"`
+	Active  bool   `xml:"Active,omitempty"`
+	Name    string `xml:"Name,omitempty"`
+	Command string `xml:"Command,omitempty"`
 }
 
 type RemoteBuildProperties struct {
-	RemoteHost               []*RemoteHost `xml:"RemoteHost"`
-	ParallelCommandExecution bool          `xml:"ParallelCommandExecution"`
+	RemoteHost               []*RemoteHost `xml:"RemoteHost,omitempty"`
+	ParallelCommandExecution bool          `xml:"ParallelCommandExecution,omitempty"`
 }
 
 type NamingCoventions struct {
-	EnableProjectSpecificSettings string `xml:"enableProjectSpecificSettings"`
-	TTCN3ModuleName               string `xml:"TTCN3ModuleName"`
-	ASN1ModuleName                string `xml:"ASN1ModuleName"`
-	Altstep                       string `xml:"altstep"`
-	GlobalConstant                string `xml:"globalConstant"`
-	ExternalConstant              string `xml:"externalConstant"`
-	Function                      string `xml:"function"`
-	ExternalFunction              string `xml:"externalFunction"`
-	ModuleParameter               string `xml:"moduleParameter"`
-	GlobalPort                    string `xml:"globalPort"`
-	GlobalTemplate                string `xml:"globalTemplate"`
-	Testcase                      string `xml:"testcase"`
-	GlobalTimer                   string `xml:"globalTimer"`
-	Type                          string `xml:"type"`
-	Group                         string `xml:"group"`
-	LocalConstant                 string `xml:"localConstant"`
-	LocalVariable                 string `xml:"localVariable"`
-	LocalTemplate                 string `xml:"localTemplate"`
-	LocalVariableTemplate         string `xml:"localVariableTemplate"`
-	LocalTimer                    string `xml:"localTimer"`
-	FormalParameter               string `xml:"formalParameter"`
-	ComponentConstant             string `xml:"componentConstant"`
-	ComponentVariable             string `xml:"componentVariable"`
-	ComponentTimer                string `xml:"componentTimer"`
+	EnableProjectSpecificSettings string `xml:"enableProjectSpecificSettings,omitempty"`
+	TTCN3ModuleName               string `xml:"TTCN3ModuleName,omitempty"`
+	ASN1ModuleName                string `xml:"ASN1ModuleName,omitempty"`
+	Altstep                       string `xml:"altstep,omitempty"`
+	GlobalConstant                string `xml:"globalConstant,omitempty"`
+	ExternalConstant              string `xml:"externalConstant,omitempty"`
+	Function                      string `xml:"function,omitempty"`
+	ExternalFunction              string `xml:"externalFunction,omitempty"`
+	ModuleParameter               string `xml:"moduleParameter,omitempty"`
+	GlobalPort                    string `xml:"globalPort,omitempty"`
+	GlobalTemplate                string `xml:"globalTemplate,omitempty"`
+	Testcase                      string `xml:"testcase,omitempty"`
+	GlobalTimer                   string `xml:"globalTimer,omitempty"`
+	Type                          string `xml:"type,omitempty"`
+	Group                         string `xml:"group,omitempty"`
+	LocalConstant                 string `xml:"localConstant,omitempty"`
+	LocalVariable                 string `xml:"localVariable,omitempty"`
+	LocalTemplate                 string `xml:"localTemplate,omitempty"`
+	LocalVariableTemplate         string `xml:"localVariableTemplate,omitempty"`
+	LocalTimer                    string `xml:"localTimer,omitempty"`
+	FormalParameter               string `xml:"formalParameter,omitempty"`
+	ComponentConstant             string `xml:"componentConstant,omitempty"`
+	ComponentVariable             string `xml:"componentVariable,omitempty"`
+	ComponentTimer                string `xml:"componentTimer,omitempty"`
 }
 
 type ConfigurationRequirements struct {
-	ConfigurationRequirement []*ConfigurationRequirementType `xml:"configurationRequirement"`
+	ConfigurationRequirement []*ConfigurationRequirementType `xml:"configurationRequirement,omitempty"`
 }
 
 type ProjectProperties struct {
-	MakefileSettings          *MakefileSettings          `xml:"MakefileSettings"`
-	LocalBuildSettings        *LocalBuildSettings        `xml:"LocalBuildSettings"`
-	RemoteBuildProperties     *RemoteBuildProperties     `xml:"RemoteBuildProperties"`
-	NamingCoventions          *NamingCoventions          `xml:"NamingCoventions"`
-	ConfigurationRequirements *ConfigurationRequirements `xml:"ConfigurationRequirements"`
+	MakefileSettings          *MakefileSettings          `xml:"MakefileSettings,omitempty"`
+	LocalBuildSettings        *LocalBuildSettings        `xml:"LocalBuildSettings,omitempty"`
+	RemoteBuildProperties     *RemoteBuildProperties     `xml:"RemoteBuildProperties,omitempty"`
+	NamingCoventions          *NamingCoventions          `xml:"NamingCoventions,omitempty"`
+	ConfigurationRequirements *ConfigurationRequirements `xml:"ConfigurationRequirements,omitempty"`
 }
 
 type FolderProperties struct {
-	ExcludeFromBuild bool              `xml:"ExcludeFromBuild"`
-	CentralStorage   bool              `xml:"centralStorage"`
-	NamingCoventions *NamingCoventions `xml:"NamingCoventions"`
+	ExcludeFromBuild bool              `xml:"ExcludeFromBuild,omitempty"`
+	CentralStorage   bool              `xml:"centralStorage,omitempty"`
+	NamingCoventions *NamingCoventions `xml:"NamingCoventions,omitempty"`
 }
 
 type FolderResource struct {
-	FolderPath       string            `xml:"FolderPath"`
-	FolderProperties *FolderProperties `xml:"FolderProperties"`
+	FolderPath       string            `xml:"FolderPath,omitempty"`
+	FolderProperties *FolderProperties `xml:"FolderProperties,omitempty"`
 }
 
 type FileProperties struct {
-	ExcludeFromBuild bool `xml:"ExcludeFromBuild"`
+	ExcludeFromBuild bool `xml:"ExcludeFromBuild,omitempty"`
 }
 
 type FileResource struct {
-	FilePath       string          `xml:"FilePath"`
-	FileProperties *FileProperties `xml:"FileProperties"`
+	FilePath       string          `xml:"FilePath,omitempty"`
+	FileProperties *FileProperties `xml:"FileProperties,omitempty"`
 }
 
 type ConfigurationType struct {
-	ProjectProperties *ProjectProperties `xml:"ProjectProperties"`
-	FolderProperties  *FolderProperties  `xml:"FolderProperties"`
-	FileProperties    *FileProperties    `xml:"FileProperties"`
+	ProjectProperties *ProjectProperties `xml:"ProjectProperties,omitempty"`
+	FolderProperties  *FolderProperties  `xml:"FolderProperties,omitempty"`
+	FileProperties    *FileProperties    `xml:"FileProperties,omitempty"`
 }
 
 type NamedConfigurationType struct {
@@ -240,9 +240,9 @@ type NamedConfigurationType struct {
 }
 
 type ConfigurationRequirementType struct {
-	ProjectName            string `xml:"projectName"`
-	RequiredConfiguration  string `xml:"requiredConfiguration"`
-	RerquiredConfiguration string `xml:"rerquiredConfiguration"`
+	ProjectName            string `xml:"projectName,omitempty"`
+	RequiredConfiguration  string `xml:"requiredConfiguration,omitempty"`
+	RerquiredConfiguration string `xml:"rerquiredConfiguration,omitempty"`
 }
 
 type ResourceType struct {
@@ -258,15 +258,15 @@ type ReferencedProject struct {
 }
 
 type ReferencedProjects struct {
-	ReferencedProject []*ReferencedProject `xml:"ReferencedProject"`
+	ReferencedProject []*ReferencedProject `xml:"ReferencedProject,omitempty"`
 }
 
 type Folders struct {
-	FolderResource []*ResourceType `xml:"FolderResource"`
+	FolderResource []*ResourceType `xml:"FolderResource,omitempty"`
 }
 
 type Files struct {
-	FileResource []*ResourceType `xml:"FileResource"`
+	FileResource []*ResourceType `xml:"FileResource,omitempty"`
 }
 
 type PathVariable struct {
@@ -275,29 +275,29 @@ type PathVariable struct {
 }
 
 type PathVariables struct {
-	PathVariable []*PathVariable `xml:"PathVariable"`
+	PathVariable []*PathVariable `xml:"PathVariable,omitempty"`
 }
 
 type Configurations struct {
-	Configuration []*NamedConfigurationType `xml:"Configuration"`
+	Configuration []*NamedConfigurationType `xml:"Configuration,omitempty"`
 }
 
 type ProjectType struct {
-	ProjectName         string              `xml:"ProjectName"`
-	ReferencedProjects  *ReferencedProjects `xml:"ReferencedProjects"`
-	Folders             *Folders            `xml:"Folders"`
-	Files               *Files              `xml:"Files"`
-	PathVariables       *PathVariables      `xml:"PathVariables"`
-	ActiveConfiguration string              `xml:"ActiveConfiguration"`
-	Configurations      *Configurations     `xml:"Configurations"`
+	ProjectName         string              `xml:"ProjectName,omitempty"`
+	ReferencedProjects  *ReferencedProjects `xml:"ReferencedProjects,omitempty"`
+	Folders             *Folders            `xml:"Folders,omitempty"`
+	Files               *Files              `xml:"Files,omitempty"`
+	PathVariables       *PathVariables      `xml:"PathVariables,omitempty"`
+	ActiveConfiguration string              `xml:"ActiveConfiguration,omitempty"`
+	Configurations      *Configurations     `xml:"Configurations,omitempty"`
 }
 
 type PackedReferencedProjectsType struct {
-	PackedReferencedProject []*ProjectType `xml:"PackedReferencedProject"`
+	PackedReferencedProject []*ProjectType `xml:"PackedReferencedProject,omitempty"`
 }
 
 type TopLevelProjectType struct {
 	VersionAttr              float64                       `xml:"version,attr"`
-	PackedReferencedProjects *PackedReferencedProjectsType `xml:"PackedReferencedProjects"`
+	PackedReferencedProjects *PackedReferencedProjectsType `xml:"PackedReferencedProjects,omitempty"`
 	*ProjectType
 }
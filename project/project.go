@@ -17,7 +17,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/nokia/ntt/internal/cache"
@@ -42,6 +45,10 @@ type Config struct {
 	// ManifestFile is the path to the manifest file.
 	ManifestFile string `json:"manifest_file"`
 
+	// TitanProjectFile is the path to the Eclipse Titan project descriptor
+	// (.tpd), if the project was configured by one.
+	TitanProjectFile string `json:"titan_project_file,omitempty"`
+
 	// Root is the root directory of the project. Usually this is the
 	// directory of the manifest file.
 	Root string
@@ -64,6 +71,13 @@ type Config struct {
 
 	// what toolchain to use.
 	toolchain string
+
+	// origins records the sources of configuration values (see Explain).
+	origins map[string][]Origin
+
+	// conflicts are the conflicting module parameters of the parameters
+	// files (see Conflicts).
+	conflicts []Conflict
 }
 
 // The Manifest file (package.yml).
@@ -89,6 +103,11 @@ type Manifest struct {
 	// test-cases. E.g. common code, adapters, codecs, ...
 	Imports []string
 
+	// Dependencies are versioned libraries, which are required to run
+	// TTCN-3 test-cases. Their directories are appended to Imports. Use
+	// ntt deps fetch to fetch them.
+	Dependencies map[string]*Dependency `json:"dependencies,omitempty"`
+
 	// BeforeBuild is a list of shell commands to be executed before
 	// building. An exit code unequal to 0 will cancel any further
 	// execution.
@@ -123,6 +142,16 @@ type Manifest struct {
 	// Diagnostics is a list of diagnostics flags used by compilator
 	Diagnostics []string `json:"diagnostics"`
 
+	// Defines is a list of preprocessor definitions (NAME or NAME=VALUE).
+	Defines []string `json:"defines,omitempty"`
+
+	// Compiler is the command line used to compile a single source file.
+	// Diagnostics flags, preprocessor defines and import directories are
+	// appended, followed by the source file. Default:
+	//
+	// 	ntt compile
+	Compiler string `json:"compiler,omitempty"`
+
 	// Parameters is an embedded parameters file.
 	Parameters `json:",inline"`
 
@@ -140,10 +169,34 @@ type Manifest struct {
 	//
 	// ${NTT_SOURCE_DIR}/ntt-lint.yml
 	LintFile string `json:"lint_file"`
+
+	// Format configures the source code formatter.
+	Format FormatConfig `json:"format,omitempty"`
+
+	// Baskets are named test selections, which can be used with ntt list
+	// --basket. A basket is either a list of filter flags (e.g. "-X @wip")
+	// or an expression composing tags, name patterns and other baskets:
+	//
+	// 	smoke: sanity + @priority:high - @slow
+	Baskets map[string]string `json:"baskets,omitempty"`
+}
+
+// FormatConfig configures the source code formatter (ntt format).
+type FormatConfig struct {
+	// Style is the formatting style. Either "canonical" (default) or
+	// "pretty".
+	Style string `json:",omitempty"`
+
+	// LineWidth is the maximum line width used by the pretty style.
+	LineWidth int `json:"line_width,omitempty"`
 }
 
 // The Parameters file provide runtime configuration for a project (e.g. parameters files)
 type Parameters struct {
+	// Include is a list of parameters files merged before this file, in
+	// the given order. Relative paths are relative to the including file.
+	Include []string `json:"include,omitempty"`
+
 	// Global test configuration
 	TestConfig `json:",inline"`
 
@@ -154,6 +207,10 @@ type Parameters struct {
 	// Execute provides a list of test specific configuration. Each entry
 	// specifies how and when a test should be executed.
 	Execute []TestConfig
+
+	// Quarantine is a list of patterns describing flaky tests. Quarantined
+	// tests are executed, but their failures do not fail the build.
+	Quarantine []string `json:"quarantine,omitempty"`
 }
 
 // A TestConfig specifies how and when a testcase should be executed.
@@ -171,10 +228,60 @@ type TestConfig struct {
 	// Module parameters
 	Parameters map[string]string `json:",omitempty"`
 
+	// Matrix expands a configuration into one job for every combination of
+	// the given module parameter values.
+	Matrix map[string][]string `json:",omitempty"`
+
+	// Retry describes if and how failed runs are repeated.
+	Retry *RetryPolicy `json:",omitempty"`
+
 	// Rules describe when a configuration should be used.
 	Rules `json:",inline"`
 }
 
+// RetryPolicy describes when and how often test runs are repeated.
+type RetryPolicy struct {
+	// Max is the maximum number of retries.
+	Max int `json:",omitempty"`
+
+	// Verdicts triggering a retry. Default is fail and error.
+	Verdicts []string `json:",omitempty"`
+
+	// Backoff is the delay before the first retry in seconds. The delay
+	// doubles with every further retry.
+	Backoff yaml.Duration `json:",omitempty"`
+}
+
+// ShouldRetry returns true if a run with the given verdict shall be repeated
+// after the given number of retries already done.
+func (p *RetryPolicy) ShouldRetry(verdict string, retries int) bool {
+	if p == nil || retries >= p.Max {
+		return false
+	}
+	verdicts := p.Verdicts
+	if len(verdicts) == 0 {
+		verdicts = []string{"fail", "error"}
+	}
+	for _, v := range verdicts {
+		if strings.EqualFold(strings.TrimSpace(v), verdict) {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns the delay before the n-th retry, counting from 1.
+func (p *RetryPolicy) Delay(n int) time.Duration {
+	if p == nil || n < 1 {
+		return 0
+	}
+	d := p.Backoff.Duration
+	for i := 1; i < n; i++ {
+		d *= 2
+	}
+	return d
+}
+
 type Rules struct {
 	// Only execute testcase if the given conditions are met.
 	Only *ExecuteCondition `json:",omitempty"`
@@ -183,9 +290,34 @@ type Rules struct {
 	Except *ExecuteCondition `json:",omitempty"`
 }
 
-// ExecuteCondition specifies conditions for executing a testcase.
+// ExecuteCondition specifies conditions for executing a testcase. A condition
+// is met if all of its non-empty fields match.
 type ExecuteCondition struct {
+	// Presets matches if any of the presets is applied.
 	Presets []string
+
+	// Env maps environment variable names to patterns. It matches if every
+	// variable matches its pattern. Undefined variables are empty.
+	Env map[string]string `json:",omitempty"`
+
+	// Tags matches if the testcase documentation has any of the tags.
+	// A tag is either a name (e.g. "@slow") or a name with a value pattern
+	// (e.g. "@priority: high").
+	Tags []string `json:",omitempty"`
+
+	// Modules matches if the testcase belongs to a module matching any of
+	// the patterns.
+	Modules []string `json:",omitempty"`
+}
+
+// A Test identifies a testcase for matching test configurations.
+type Test struct {
+	// Name is the qualified name of the testcase.
+	Name string
+
+	// Tags are the tags of the testcase documentation, as returned by
+	// doc.FindAllTags.
+	Tags [][]string
 }
 
 // Index specifies where to look for project configuration files.
@@ -217,6 +349,27 @@ var (
 	IndexFile    = "ttcn3_suites.json"
 )
 
+// ReadIndex reads an index file. Relative directories of suites are resolved
+// relative to the directory of the index file.
+func ReadIndex(file string) (*Index, error) {
+	b, err := fs.Content(file)
+	if err != nil {
+		return nil, err
+	}
+	var idx Index
+	if err := json.Unmarshal(b, &idx); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	base := filepath.Dir(file)
+	idx.SourceDir = fs.Real(base, idx.SourceDir)
+	idx.BinaryDir = fs.Real(base, idx.BinaryDir)
+	for i, s := range idx.Suites {
+		idx.Suites[i].RootDir = fs.Real(base, s.RootDir)
+		idx.Suites[i].SourceDir = fs.Real(base, s.SourceDir)
+	}
+	return &idx, nil
+}
+
 // Discover walks towards the file system root and collects
 // known test suite layouts.
 //
@@ -228,23 +381,17 @@ func Discover(path string) []Suite {
 
 	var list []Suite
 
-	// Return index, ignoring errors.
+	// Return suites of index, ignoring errors.
 	readIndices := func(file string) []Suite {
-		b, err := fs.Content(file)
+		idx, err := ReadIndex(file)
 		if err != nil {
-			log.Debugf("Failed to read %s: %s", file, err.Error())
+			log.Debugf("Failed to read %s: %s\n", file, err.Error())
 			return nil
 		}
-		var idx Index
-		if err := json.Unmarshal(b, &idx); err != nil {
-			log.Debugf("%s: %s", file, err.Error())
-		}
-
 		var list []Suite
 		for _, s := range idx.Suites {
 			if s.RootDir != "" {
-				root := fs.Real(filepath.Dir(file), s.RootDir)
-				log.Debugf("using root_dir: %q\n", root)
+				log.Debugf("using root_dir: %q\n", s.RootDir)
 				list = append(list, s)
 			}
 		}
@@ -256,6 +403,9 @@ func Discover(path string) []Suite {
 		if file := fs.JoinPath(path, ManifestFile); fs.IsRegular(file) {
 			log.Debugf("discovered manifest: %q\n", file)
 			list = append(list, Suite{RootDir: path, SourceDir: path})
+		} else if tpds := fs.Glob(fs.JoinPath(path, "*"+TitanProjectExt)); len(tpds) == 1 {
+			log.Debugf("discovered titan project: %q\n", tpds[0])
+			list = append(list, Suite{RootDir: path, SourceDir: path})
 		}
 		list = append(list, readIndices(fs.JoinPath(path, IndexFile))...)
 
@@ -380,6 +530,13 @@ func (p *Parameters) GlobalConfig(presets ...string) (TestConfig, error) {
 // TestConfigs returns the test configurations matching the given name and
 // applied presets.
 func (p *Parameters) TestConfigs(name string, presets ...string) ([]TestConfig, error) {
+	return p.TestConfigsFor(Test{Name: name}, presets...)
+}
+
+// TestConfigsFor is like TestConfigs, but additionally matches the tags of
+// the testcase documentation. Configurations with a matrix are expanded into
+// one configuration per parameter combination.
+func (p *Parameters) TestConfigsFor(t Test, presets ...string) ([]TestConfig, error) {
 	gc, err := p.GlobalConfig(presets...)
 	if err != nil {
 		return nil, err
@@ -388,49 +545,85 @@ func (p *Parameters) TestConfigs(name string, presets ...string) ([]TestConfig,
 	var list []TestConfig
 	for _, tc := range p.Execute {
 		tc = MergeTestConfig(gc, tc)
-		if tc, ok := matchTestConfig(name, tc, presets...); ok {
-			list = append(list, tc)
+		if tc, ok := matchTestConfig(t, tc, presets...); ok {
+			list = append(list, expandMatrix(tc)...)
 		}
 	}
 
 	// Our job is done if we have at least one match.
 	if len(list) == 0 {
-		if tc, ok := matchTestConfig(name, p.TestConfig, presets...); ok {
-			list = append(list, tc)
+		if tc, ok := matchTestConfig(t, p.TestConfig, presets...); ok {
+			list = append(list, expandMatrix(tc)...)
 		}
 	}
 
 	return list, nil
 }
 
-func matchTestConfig(name string, tc TestConfig, presets ...string) (TestConfig, bool) {
+func matchTestConfig(t Test, tc TestConfig, presets ...string) (TestConfig, bool) {
 	pattern, params := split(tc.Test)
 	if pattern != "" {
-		ok, err := filepath.Match(pattern, name)
+		ok, err := filepath.Match(pattern, t.Name)
 		if err != nil {
-			log.Verbosef("%s: %s\n", name, err.Error())
+			log.Verbosef("%s: %s\n", t.Name, err.Error())
 		}
 		if !ok {
 			return tc, false
 		}
 	}
 
-	tc.Test = name
+	tc.Test = t.Name
 	if params != "" {
 		tc.Test += "(" + params + ")"
 	}
 
-	return tc, matchRules(tc.Rules, presets...)
+	return tc, matchRules(tc.Rules, t, presets...)
+}
+
+// matchRules returns true if test and presets match given rules
+func matchRules(r Rules, t Test, presets ...string) bool {
+	if r.Except != nil && !r.Except.empty() && r.Except.match(t, presets...) {
+		return false
+	}
+	if r.Only != nil && !r.Only.match(t, presets...) {
+		return false
+	}
+	return true
+}
+
+func (c *ExecuteCondition) empty() bool {
+	return len(c.Presets) == 0 && len(c.Env) == 0 && len(c.Tags) == 0 && len(c.Modules) == 0
 }
 
-// matchRules returns true if presets match given rules
-func matchRules(r Rules, presets ...string) bool {
-	if r.Except != nil && matchPresets(r.Except, presets...) {
+// match returns true if all non-empty fields of the condition match.
+func (c *ExecuteCondition) match(t Test, presets ...string) bool {
+	if len(c.Presets) > 0 && !matchPresets(c, presets...) {
 		return false
 	}
-	if r.Only != nil && len(r.Only.Presets) > 0 && !matchPresets(r.Only, presets...) {
+	for k, pattern := range c.Env {
+		if !matchPattern(pattern, env.Getenv(k)) {
+			return false
+		}
+	}
+	if len(c.Tags) > 0 && !matchTags(c.Tags, t.Tags) {
 		return false
 	}
+	if len(c.Modules) > 0 {
+		module := t.Name
+		if i := strings.Index(module, "."); i >= 0 {
+			module = module[:i]
+		}
+		ok := false
+		for _, pattern := range c.Modules {
+			if matchPattern(pattern, module) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
 	return true
 }
 
@@ -446,6 +639,66 @@ func matchPresets(c *ExecuteCondition, presets ...string) bool {
 	return false
 }
 
+// matchTags returns true if any of the wanted tags is found in tags.
+func matchTags(wanted []string, tags [][]string) bool {
+	for _, w := range wanted {
+		name, value := w, ""
+		if i := strings.IndexAny(w, ": \t"); i >= 0 {
+			name, value = w[:i], strings.TrimLeft(w[i:], ": \t")
+		}
+		for _, tag := range tags {
+			if tag[0] == name && (value == "" || matchPattern(value, tag[1])) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchPattern(pattern, s string) bool {
+	ok, err := filepath.Match(pattern, s)
+	if err != nil {
+		log.Verbosef("%s: %s\n", pattern, err.Error())
+	}
+	return ok
+}
+
+// expandMatrix returns a configuration for every combination of the matrix
+// values. Combinations are ordered by parameter name, the last name varying
+// fastest.
+func expandMatrix(tc TestConfig) []TestConfig {
+	if len(tc.Matrix) == 0 {
+		return []TestConfig{tc}
+	}
+
+	keys := make([]string, 0, len(tc.Matrix))
+	for k := range tc.Matrix {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list := []TestConfig{tc}
+	for _, k := range keys {
+		var next []TestConfig
+		for _, base := range list {
+			for _, v := range tc.Matrix[k] {
+				job := base
+				job.Parameters = make(map[string]string, len(base.Parameters)+1)
+				for k, v := range base.Parameters {
+					job.Parameters[k] = v
+				}
+				job.Parameters[k] = v
+				next = append(next, job)
+			}
+		}
+		list = next
+	}
+	for i := range list {
+		list[i].Matrix = nil
+	}
+	return list
+}
+
 // mergeParameters merges the given parameters. Scalar values from b override
 // values from a. Maps are merged and arrays are appended.
 func mergeParameters(a, b Parameters) Parameters {
@@ -462,9 +715,22 @@ func mergeParameters(a, b Parameters) Parameters {
 		result.Presets = nil
 	}
 	result.Execute = append(a.Execute, b.Execute...)
+	result.Quarantine = append(a.Quarantine, b.Quarantine...)
 	return result
 }
 
+// IsQuarantined returns true if the test with the given name matches any
+// pattern of the quarantine list. Testcase parameters are ignored.
+func (p *Parameters) IsQuarantined(name string) bool {
+	name, _ = split(name)
+	for _, pattern := range p.Quarantine {
+		if matchPattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // MergeTestConfig merges two test configurations. Scalar values from b override
 // values from a. Maps are merged. Arrays are appended.
 func MergeTestConfig(a, b TestConfig) TestConfig {
@@ -488,6 +754,20 @@ func MergeTestConfig(a, b TestConfig) TestConfig {
 	if len(result.Parameters) == 0 {
 		result.Parameters = nil
 	}
+	result.Matrix = make(map[string][]string)
+	for k, v := range a.Matrix {
+		result.Matrix[k] = v
+	}
+	for k, v := range b.Matrix {
+		result.Matrix[k] = v
+	}
+	if len(result.Matrix) == 0 {
+		result.Matrix = nil
+	}
+	result.Retry = a.Retry
+	if b.Retry != nil {
+		result.Retry = b.Retry
+	}
 	// Should we return an error if a and b have conflicting execute conditions?
 	result.Only = b.Only
 	result.Except = b.Except
@@ -507,10 +787,12 @@ func split(name string) (string, string) {
 // Without any arguments Open will open the current working directory, unless
 // environment variable NTT_SOURCE_DIR is set.
 //
-// If you pass a manifest file as single argument, Open will use it directly.
+// If you pass a manifest file or a Titan project descriptor (.tpd) as single
+// argument, Open will use it directly.
 //
 // If you pass a directory as single argument, Open will first look for a
-// package.yml and use it.
+// package.yml and use it. If there is no package.yml, but exactly one .tpd
+// file, Open will use the Titan project descriptor.
 //
 // If no package.yml is found Open will look for TTCN-3 source files. It will
 // look recursively if directory contains typical project root files (i.e.
@@ -541,12 +823,15 @@ func Open(args ...string) (*Config, error) {
 		return NewConfig(WithSources(args...), defaults)
 	}
 
-	// Treat a single file argument as source, unless it is a directory or
-	// manifest file.
+	// Treat a single file argument as source, unless it is a directory,
+	// manifest file or Titan project descriptor.
 	if file := args[0]; fs.IsRegular(file) {
 		if filepath.Base(file) == ManifestFile {
 			return NewConfig(WithManifest(file), defaults)
 		}
+		if filepath.Ext(file) == TitanProjectExt {
+			return NewConfig(WithTitanProject(file), defaults)
+		}
 		return NewConfig(WithSources(file), defaults)
 	}
 
@@ -559,7 +844,13 @@ func NewConfig(opts ...ConfigOption) (*Config, error) {
 		return nil, err
 	}
 
-	if c.ParametersFile != "" {
+	// The manifest is merged first, followed by the parameters file. Both
+	// may include further parameters files.
+	l := includer{vars: c.Variables}
+	if err := l.add(c.ManifestFile, c.Parameters); err != nil {
+		return nil, err
+	}
+	if _, ok := l.deps[c.ParametersFile]; c.ParametersFile != "" && !ok {
 		var pf Parameters
 		b, err := fs.Content(c.ParametersFile)
 		if err != nil {
@@ -568,7 +859,28 @@ func NewConfig(opts ...ConfigOption) (*Config, error) {
 		if err := yaml.Unmarshal(b, &pf); err != nil {
 			return nil, err
 		}
-		c.Parameters = mergeParameters(c.Parameters, pf)
+		if err := l.add(c.ParametersFile, pf); err != nil {
+			return nil, err
+		}
+	}
+	// The manifest has been recorded already. Files included by the
+	// manifest are merged before it.
+	before := c.ManifestFile
+	for _, f := range l.files {
+		if f.file == c.ManifestFile {
+			before = ""
+			continue
+		}
+		for key, v := range structValues(reflect.ValueOf(f.params)) {
+			if key != "include" && !v.IsZero() {
+				c.recordBefore(key, f.file, v.Interface(), before)
+			}
+		}
+	}
+	c.Parameters = l.merge()
+	c.conflicts = l.conflicts
+	for _, x := range c.conflicts {
+		log.Verboseln(x.String())
 	}
 
 	return c, nil
@@ -621,6 +933,7 @@ func WithIndex(file string) ConfigOption {
 				if os.SameFile(info, info2) {
 					log.Debugf("project: using source dir %s\n", s.SourceDir)
 					c.SourceDir = fs.Real(base, s.SourceDir)
+					c.record("source_dir", file, s.SourceDir)
 				}
 			}
 		}
@@ -638,6 +951,7 @@ func WithManifest(file string) ConfigOption {
 	return func(c *Config) error {
 		c.ManifestFile = file
 		c.Root = filepath.Dir(file)
+		c.record("root", "directory of "+file, c.Root)
 		b, err := fs.Content(file)
 		if err != nil {
 			return err
@@ -647,12 +961,17 @@ func WithManifest(file string) ConfigOption {
 				return fmt.Errorf("%s: %w", file, err)
 			}
 		}
-		c.updateVariables()
+		c.recordManifest(file)
+		c.updateVariables(file)
 		if err := c.Variables.Expand(); err != nil {
 			return err
 		}
 		env.ExpandAll(&c.Manifest, c.Variables)
 		c.Manifest.expandPaths(c.Root)
+		if dirs := c.dependencyDirs(); len(dirs) > 0 {
+			c.Imports = append(c.Imports, dirs...)
+			c.record("imports", "dependencies of "+file, c.Imports)
+		}
 		log.Debugf("project: using manifest %s\n", file)
 		return nil
 	}
@@ -662,6 +981,7 @@ func WithManifest(file string) ConfigOption {
 func WithRoot(root string) ConfigOption {
 	return func(c *Config) error {
 		c.Root = root
+		c.record("root", "argument", root)
 		return nil
 	}
 }
@@ -670,6 +990,7 @@ func WithRoot(root string) ConfigOption {
 func WithSourceDir(dir string) ConfigOption {
 	return func(c *Config) error {
 		c.SourceDir = dir
+		c.record("source_dir", "argument", dir)
 		return nil
 	}
 }
@@ -678,6 +999,7 @@ func WithSourceDir(dir string) ConfigOption {
 func WithSources(srcs ...string) ConfigOption {
 	return func(c *Config) error {
 		c.Sources = srcs
+		c.record("sources", "argument", srcs)
 		return nil
 	}
 }
@@ -686,6 +1008,7 @@ func WithSources(srcs ...string) ConfigOption {
 func WithImports(dirs ...string) ConfigOption {
 	return func(c *Config) error {
 		c.Imports = dirs
+		c.record("imports", "argument", dirs)
 		return nil
 	}
 }
@@ -694,7 +1017,8 @@ func WithImports(dirs ...string) ConfigOption {
 // imports directories.
 //
 // If the root directory contains a package.yml, sources and imports are set
-// from the manifest exclusively.
+// from the manifest exclusively. Otherwise, if the root directory contains
+// exactly one Titan project descriptor (.tpd), it is used instead.
 //
 // AutomaticRoot will load TTCN-3 source files recursively, if the root
 // directory contains typical project root files (e.g. build.sh, testcases/,
@@ -706,10 +1030,14 @@ func WithImports(dirs ...string) ConfigOption {
 func AutomaticRoot(root string) ConfigOption {
 	return func(c *Config) error {
 		c.Root = root
+		c.record("root", "argument", root)
 		log.Debugf("project: root %s\n", root)
 		if manifest := fs.JoinPath(root, ManifestFile); fs.IsRegular(manifest) {
 			return WithManifest(manifest)(c)
 		}
+		if tpds := fs.Glob(fs.JoinPath(root, "*"+TitanProjectExt)); len(tpds) == 1 {
+			return WithTitanProject(tpds[0])(c)
+		}
 
 		if isRoot(c.Root) {
 			log.Debugln("project: scanning recursively...")
@@ -722,6 +1050,7 @@ func AutomaticRoot(root string) ConfigOption {
 			s = s[:200] + fmt.Sprintf("...] (%d files/directories)", len(c.Sources))
 		}
 		log.Debugf("project: use sources: %s\n", s)
+		c.record("sources", "TTCN-3 files of "+c.Root, c.Sources)
 
 		commonDirs := []string{
 			"../../../sct",
@@ -744,6 +1073,9 @@ func AutomaticRoot(root string) ConfigOption {
 			s = s[:200] + fmt.Sprintf("...] (%d directories)", len(c.Imports))
 		}
 		log.Debugf("project: use imports: %s\n", s)
+		if len(c.Imports) > 0 {
+			c.record("imports", "common directories of "+c.Root, c.Imports)
+		}
 
 		return nil
 	}
@@ -778,6 +1110,7 @@ func AutomaticEnv() ConfigOption {
 			}
 			if used {
 				log.Debugf("project: using environment variable %s=%s\n", k, v)
+				c.record(strings.ToLower(strings.TrimPrefix(k, "NTT_")), "environment variable "+k, v)
 			}
 		}
 		return nil
@@ -791,25 +1124,30 @@ func WithDefaults() ConfigOption {
 			switch {
 			case c.SourceDir != "":
 				c.Root = c.SourceDir
+				c.record("root", "default (source_dir)", c.Root)
 			case len(c.Sources) > 0:
 				c.Root = filepath.Dir(c.Sources[0])
+				c.record("root", "default (directory of first source)", c.Root)
 				// When there's no root, but only source, we want the suite to be named after the source.
 				n, err := NameFromURI(c.Sources[0])
 				if err != nil {
 					return err
 				}
 				c.Name = n
+				c.record("name", "default (name of first source)", c.Name)
 			default:
 				cwd, err := os.Getwd()
 				if err != nil {
 					return err
 				}
 				c.Root = cwd
+				c.record("root", "default (working directory)", c.Root)
 			}
 			log.Debugf("project: using default root %s\n", c.Root)
 		}
 		if c.SourceDir == "" {
 			c.SourceDir = c.Root
+			c.record("source_dir", "default (root)", c.SourceDir)
 			log.Debugf("project: using default source dir %s\n", c.SourceDir)
 		}
 		if c.Name == "" {
@@ -818,6 +1156,7 @@ func WithDefaults() ConfigOption {
 				return err
 			}
 			c.Name = n
+			c.record("name", "default (name of root)", c.Name)
 			log.Debugf("project: using default name %s\n", c.Name)
 		}
 		defaultFile := func(name string) string {
@@ -835,18 +1174,21 @@ func WithDefaults() ConfigOption {
 		if c.ParametersFile == "" {
 			if path := defaultFile(fmt.Sprintf("%s.parameters", c.Name)); path != "" {
 				c.ParametersFile = path
+				c.record("parameters_file", "default", path)
 				log.Debugf("project: using parameters file %s\n", c.ParametersFile)
 			}
 		}
 		if c.HooksFile == "" {
 			if path := defaultFile(fmt.Sprintf("%s.hooks", c.Name)); path != "" {
 				c.HooksFile = path
+				c.record("hooks_file", "default", path)
 				log.Debugf("project: using hooks file %s\n", c.HooksFile)
 			}
 		}
 		if c.LintFile == "" {
 			if path := defaultFile("ntt-lint.yml"); path != "" {
 				c.LintFile = path
+				c.record("lint_file", "default", path)
 				log.Debugf("project: using lint file %s\n", c.LintFile)
 			}
 		}
@@ -861,8 +1203,9 @@ func WithDefaults() ConfigOption {
 		} else {
 			c.ResultsFile = results.Filename
 		}
+		c.record("results_file", "default", c.ResultsFile)
 
-		c.updateVariables()
+		c.updateVariables("")
 		return nil
 	}
 }
@@ -879,20 +1222,37 @@ func isRoot(root string) bool {
 
 // updateVariables updates the given variable with the variables from
 // environment files. Environment variables override environment files.
-// Environment files overwrite manifest variables.
-func (m *Manifest) updateVariables() {
+// Environment files overwrite manifest variables. Manifest is the manifest
+// file the variables were read from, if any.
+func (c *Config) updateVariables(manifest string) {
+	m := &c.Manifest
 	if m.Variables == nil {
 		m.Variables = make(map[string]string)
 	}
+	if manifest != "" {
+		for k, v := range m.Variables {
+			c.record("variables."+k, manifest, v)
+		}
+	}
+
+	// Environment files are parsed one by one to record their origin.
+	// Earlier files take precedence.
+	for i := len(env.Files) - 1; i >= 0; i-- {
+		for k, v := range env.ParseFiles(env.Files[i]) {
+			c.record("variables."+k, cache.Lookup(env.Files[i]), v)
+		}
+	}
 	for k, v := range env.ParseFiles() {
 		if s, ok := env.LookupEnv(k); ok {
 			v = s
+			c.record("variables."+k, "environment variable "+k, v)
 		}
 		m.Variables[k] = v
 	}
 	for k, v := range env.EnvironMap() {
 		if strings.HasPrefix(k, "NTT_") || strings.HasPrefix(k, "K3_") || strings.HasPrefix(k, "SCT_") {
 			m.Variables[k] = v
+			c.record("variables."+k, "environment variable "+k, v)
 		}
 	}
 
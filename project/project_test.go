@@ -350,10 +350,10 @@ func TestRules(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(t.Name(), func(t *testing.T) {
 			rules := Rules{
-				Except: &ExecuteCondition{tt.Except},
-				Only:   &ExecuteCondition{tt.Only},
+				Except: &ExecuteCondition{Presets: tt.Except},
+				Only:   &ExecuteCondition{Presets: tt.Only},
 			}
-			got := matchRules(rules, tt.Presets...)
+			got := matchRules(rules, Test{}, tt.Presets...)
 			assert.Equal(t, tt.Want, got, fmt.Sprintf("Presets: %v, Except: %v, Only: %v", tt.Presets, tt.Except, tt.Only))
 		})
 	}
@@ -412,6 +412,119 @@ func TestTestConfigs(t *testing.T) {
 	}
 }
 
+func TestConditions(t *testing.T) {
+	t.Setenv("NTT_TARGET", "x86_64")
+	tests := []struct {
+		Condition string
+		Test      Test
+		Want      bool
+	}{
+		{Condition: `{}`, Want: true},
+		{Condition: `{env: {NTT_TARGET: x86*}}`, Want: true},
+		{Condition: `{env: {NTT_TARGET: arm*}}`, Want: false},
+		{Condition: `{env: {NTT_UNDEFINED: ""}}`, Want: true},
+		{Condition: `{env: {NTT_TARGET: x86*, NTT_UNDEFINED: "?*"}}`, Want: false},
+
+		{Condition: `{modules: [A*]}`, Test: Test{Name: "A1.TC"}, Want: true},
+		{Condition: `{modules: [A*]}`, Test: Test{Name: "B1.TC"}, Want: false},
+		{Condition: `{modules: [A, B]}`, Test: Test{Name: "B.TC"}, Want: true},
+
+		{Condition: `{tags: ["@slow"]}`, Test: Test{Tags: [][]string{{"@slow", ""}}}, Want: true},
+		{Condition: `{tags: ["@slow"]}`, Test: Test{Tags: [][]string{{"@fast", ""}}}, Want: false},
+		{Condition: `{tags: ["@priority: high"]}`, Test: Test{Tags: [][]string{{"@priority", "high"}}}, Want: true},
+		{Condition: `{tags: ["@priority: high"]}`, Test: Test{Tags: [][]string{{"@priority", "low"}}}, Want: false},
+		{Condition: `{tags: ["@priority"]}`, Test: Test{Tags: [][]string{{"@priority", "low"}}}, Want: true},
+
+		{Condition: `{modules: [A], tags: ["@slow"]}`, Test: Test{Name: "A.TC", Tags: [][]string{{"@slow", ""}}}, Want: true},
+		{Condition: `{modules: [A], tags: ["@slow"]}`, Test: Test{Name: "B.TC", Tags: [][]string{{"@slow", ""}}}, Want: false},
+	}
+	for _, tt := range tests {
+		var c ExecuteCondition
+		if err := yaml.Unmarshal([]byte(tt.Condition), &c); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, tt.Want, c.match(tt.Test), tt.Condition)
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	p := NewParameters(t, `
+parameters: {X: "0"}
+matrix: {B: ["1", "2"]}
+execute:
+  - test: "A.*"
+    matrix: {A: ["x", "y"]}
+  - test: "A.TC"
+    except: {tags: ["@slow"]}
+`)
+	actual, err := p.TestConfigsFor(Test{Name: "A.TC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var params []map[string]string
+	for _, tc := range actual {
+		assert.Nil(t, tc.Matrix)
+		params = append(params, tc.Parameters)
+	}
+	assert.Equal(t, []map[string]string{
+		{"A": "x", "B": "1", "X": "0"},
+		{"A": "x", "B": "2", "X": "0"},
+		{"A": "y", "B": "1", "X": "0"},
+		{"A": "y", "B": "2", "X": "0"},
+		{"B": "1", "X": "0"},
+		{"B": "2", "X": "0"},
+	}, params)
+
+	actual, err = p.TestConfigsFor(Test{Name: "A.TC", Tags: [][]string{{"@slow", ""}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 4, len(actual))
+}
+
+func TestQuarantine(t *testing.T) {
+	a := NewParameters(t, `quarantine: ["A.TC1"]`)
+	b := NewParameters(t, `quarantine: ["B.*"]`)
+	p := mergeParameters(*a, *b)
+	assert.Equal(t, []string{"A.TC1"}, a.Quarantine)
+	assert.True(t, p.IsQuarantined("A.TC1"))
+	assert.True(t, p.IsQuarantined("A.TC1(23)"))
+	assert.False(t, p.IsQuarantined("A.TC2"))
+	assert.True(t, p.IsQuarantined("B.TC2"))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	p := NewParameters(t, `
+retry: {max: 1}
+execute:
+  - test: "A.*"
+    retry: {max: 3, verdicts: [fail, inconc], backoff: 0.5}
+`)
+	actual, err := p.TestConfigs("A.TC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := actual[0].Retry
+	assert.Equal(t, 3, r.Max)
+	assert.True(t, r.ShouldRetry("inconc", 2))
+	assert.False(t, r.ShouldRetry("inconc", 3))
+	assert.False(t, r.ShouldRetry("error", 0))
+	assert.Equal(t, []time.Duration{0, 500 * time.Millisecond, time.Second, 2 * time.Second},
+		[]time.Duration{r.Delay(0), r.Delay(1), r.Delay(2), r.Delay(3)})
+
+	actual, err = p.TestConfigs("B.TC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = actual[0].Retry
+	assert.True(t, r.ShouldRetry("error", 0))
+	assert.False(t, r.ShouldRetry("pass", 0))
+	assert.Equal(t, time.Duration(0), r.Delay(1))
+
+	var none *RetryPolicy
+	assert.False(t, none.ShouldRetry("fail", 0))
+}
+
 func NewParameters(t *testing.T, s string) *Parameters {
 	var p Parameters
 	if err := yaml.Unmarshal([]byte(s), &p); err != nil {
@@ -0,0 +1,124 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"text/template"
+
+	"github.com/nokia/ntt/ttcn3/syntax"
+)
+
+// Scaffold returns the files of a new test suite, keyed by file name: a
+// manifest, an example module with a testcase and a control part, a
+// parameters file with a preset and a lint configuration. If cmake is true, a
+// CMakeLists.txt using FindNTT.cmake is added.
+//
+// The parameters and lint files are placed where WithDefaults expects them and
+// are additionally referenced by the manifest.
+//
+// The name is used as module identifier and must be a valid TTCN-3
+// identifier.
+func Scaffold(name string, cmake bool) (map[string][]byte, error) {
+	if !identifier.MatchString(name) || syntax.Lookup([]byte(name)) != syntax.IDENT {
+		return nil, fmt.Errorf("%q is not a valid TTCN-3 identifier", name)
+	}
+
+	files := map[string]*template.Template{
+		ManifestFile:         manifestTemplate,
+		name + ".ttcn3":      moduleTemplate,
+		name + ".parameters": parametersTemplate,
+		"ntt-lint.yml":       lintTemplate,
+	}
+	if cmake {
+		files["CMakeLists.txt"] = cmakeTemplate
+	}
+
+	ret := make(map[string][]byte)
+	for file, tmpl := range files {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, struct{ Name string }{name}); err != nil {
+			return nil, err
+		}
+		ret[file] = buf.Bytes()
+	}
+	return ret, nil
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+var manifestTemplate = template.Must(template.New(ManifestFile).Parse(`# Test suite manifest. See ntt check-config --schema for all keys.
+name: {{.Name}}
+
+# TTCN-3 source files or directories providing the testcases.
+sources:
+  - {{.Name}}.ttcn3
+
+# Directories with TTCN-3 libraries required by the testcases.
+imports: []
+
+parameters_file: {{.Name}}.parameters
+lint_file: ntt-lint.yml
+`))
+
+var moduleTemplate = template.Must(template.New("module").Parse(`module {{.Name}}
+{
+	type component C {}
+
+	// Example test parameter. See {{.Name}}.parameters.
+	modulepar boolean DEBUG := false;
+
+	testcase TC_Example() runs on C
+	{
+		if (DEBUG) {
+			log("running TC_Example");
+		}
+		setverdict(pass);
+	}
+
+	control
+	{
+		execute(TC_Example());
+	}
+}
+`))
+
+var parametersTemplate = template.Must(template.New("parameters").Parse(`# Global test configuration.
+timeout: 10
+
+# Presets are named configurations, which can be activated on demand.
+presets:
+  debug:
+    parameters:
+      {{.Name}}.DEBUG: "true"
+
+# Test specific configurations.
+execute:
+  - test: {{.Name}}.TC_*
+`))
+
+var lintTemplate = template.Must(template.New("lint").Parse(`# Lint configuration. See ntt lint --help for all checks.
+require_case_else: true
+max_lines: 100
+
+complexity:
+  max: 15
+
+naming:
+  tests:
+    "^TC_": "testcase identifiers must begin with TC_"
+`))
+
+var cmakeTemplate = template.Must(template.New("cmake").Parse(`cmake_minimum_required(VERSION 3.0)
+project({{.Name}})
+
+# FindNTT.cmake is located in the cmake directory of ntt.
+# list(APPEND CMAKE_MODULE_PATH "/path/to/ntt/cmake")
+find_package(NTT REQUIRED)
+
+add_ttcn3_suite({{.Name}}
+    NAME {{.Name}}
+    SOURCES {{.Name}}.ttcn3
+    PARAMETERS_FILE ${CMAKE_CURRENT_SOURCE_DIR}/{{.Name}}.parameters
+)
+`))
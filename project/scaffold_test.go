@@ -0,0 +1,54 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaffold(t *testing.T) {
+	files, err := Scaffold("mysuite", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for name, b := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.Contains(t, files, "CMakeLists.txt")
+
+	c, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "mysuite", c.Name)
+	assert.Equal(t, []string{filepath.Join(dir, "mysuite.ttcn3")}, c.Sources)
+	assert.Equal(t, filepath.Join(dir, "mysuite.parameters"), c.ParametersFile)
+	assert.Equal(t, filepath.Join(dir, "ntt-lint.yml"), c.LintFile)
+
+	problems, err := CheckManifest(filepath.Join(dir, ManifestFile))
+	assert.Nil(t, err)
+	assert.Empty(t, problems)
+
+	tcs, err := c.TestConfigs("mysuite.TC_Example", "debug")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(tcs))
+	assert.Equal(t, "true", tcs[0].Parameters["mysuite.DEBUG"])
+
+	tree := ttcn3.ParseFile(filepath.Join(dir, "mysuite.ttcn3"))
+	assert.Nil(t, tree.Err)
+	assert.Equal(t, 1, len(tree.Tests()))
+}
+
+func TestScaffoldInvalidName(t *testing.T) {
+	for _, name := range []string{"my-suite", "1suite", "module", ""} {
+		_, err := Scaffold(name, false)
+		assert.ErrorContains(t, err, "not a valid TTCN-3 identifier", name)
+	}
+}
@@ -0,0 +1,92 @@
+package project
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// SchemaURL is the base URL of the published JSON schemas.
+const SchemaURL = "https://raw.githubusercontent.com/nokia/ntt/master/project/schema/"
+
+// ManifestSchema returns the JSON Schema of the manifest file (package.yml).
+func ManifestSchema() ([]byte, error) {
+	return schema("package.schema.json", "ntt manifest (package.yml)", reflect.TypeOf(Manifest{}))
+}
+
+// ParametersSchema returns the JSON Schema of parameters files.
+func ParametersSchema() ([]byte, error) {
+	return schema("parameters.schema.json", "ntt parameters file", reflect.TypeOf(Parameters{}))
+}
+
+func schema(id string, title string, t reflect.Type) ([]byte, error) {
+	defs := make(map[string]interface{})
+	s := typeSchema(t, defs)
+	s["$schema"] = "http://json-schema.org/draft-07/schema#"
+	s["$id"] = SchemaURL + id
+	s["title"] = title
+	if len(defs) > 0 {
+		s["definitions"] = defs
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// typeSchema returns the JSON Schema of type t. Struct types other than t
+// itself are added to defs and referenced.
+func typeSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == durationType {
+		return map[string]interface{}{
+			"type":        "number",
+			"description": "duration in seconds",
+		}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		props := make(map[string]interface{})
+		for name, ft := range structFields(t) {
+			props[name] = ref(ft, defs)
+		}
+		return map[string]interface{}{
+			"type":                 []string{"object", "null"},
+			"properties":           props,
+			"additionalProperties": false,
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 []string{"object", "null"},
+			"additionalProperties": ref(t.Elem(), defs),
+		}
+	case reflect.Slice:
+		return map[string]interface{}{
+			"type":  []string{"array", "null"},
+			"items": ref(t.Elem(), defs),
+		}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	}
+	return map[string]interface{}{"type": []string{"string", "number", "boolean"}}
+}
+
+// ref returns a reference to the definition of a named struct type or the
+// schema of t, if t is not a struct.
+func ref(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == durationType {
+		return typeSchema(t, defs)
+	}
+	if _, ok := defs[t.Name()]; !ok {
+		defs[t.Name()] = nil
+		defs[t.Name()] = typeSchema(t, defs)
+	}
+	return map[string]interface{}{"$ref": "#/definitions/" + t.Name()}
+}
@@ -0,0 +1,507 @@
+package project
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/project/internal/titan"
+)
+
+// TitanProjectExt is the file extension of Eclipse Titan project descriptors.
+const TitanProjectExt = ".tpd"
+
+// WithTitanProject reads an Eclipse Titan project descriptor (.tpd file).
+//
+// Folders and TTCN-3 files of the project become sources. Folders include all
+// sub-directories containing TTCN-3 files. Resources excluded from build by
+// the active configuration are omitted. Sources of referenced projects
+// become imports. Preprocessor defines of the active build configuration are
+// added to Defines.
+func WithTitanProject(file string) ConfigOption {
+	return func(c *Config) error {
+		tpd, err := readTitanProject(file)
+		if err != nil {
+			return err
+		}
+
+		c.TitanProjectFile = file
+		c.Root = filepath.Dir(file)
+		if tpd.ProjectName != "" {
+			c.Name = tpd.ProjectName
+		}
+		c.Sources = titanSources(file, tpd.ProjectType, "")
+
+		conf := titanConfiguration(tpd.ProjectType, "")
+		if conf != nil && conf.ProjectProperties != nil {
+			if ms := conf.ProjectProperties.MakefileSettings; ms != nil {
+				if ms.TTCN3preprocessorDefines != nil {
+					c.Defines = append(c.Defines, ms.TTCN3preprocessorDefines.ListItem...)
+				}
+				if ms.PreprocessorDefines != nil {
+					c.Defines = append(c.Defines, ms.PreprocessorDefines.ListItem...)
+				}
+			}
+		}
+
+		visited := map[string]bool{filepath.Clean(file): true}
+		var walk func(file string, p *titan.ProjectType, conf *titan.NamedConfigurationType)
+		walk = func(file string, p *titan.ProjectType, conf *titan.NamedConfigurationType) {
+			if p.ReferencedProjects == nil {
+				return
+			}
+			for _, ref := range p.ReferencedProjects.ReferencedProject {
+				path := titanReference(file, p, ref)
+				if path == "" {
+					log.Printf("%s: referenced project %q not found\n", file, ref.NameAttr)
+					continue
+				}
+				if visited[path] {
+					continue
+				}
+				visited[path] = true
+				rp, err := readTitanProject(path)
+				if err != nil {
+					log.Printf("%s: %s\n", file, err.Error())
+					continue
+				}
+				log.Debugf("project: using referenced titan project %s\n", path)
+				c.Imports = append(c.Imports, titanSources(path, rp.ProjectType, requiredConfiguration(conf, ref.NameAttr))...)
+				walk(path, rp.ProjectType, titanConfiguration(rp.ProjectType, requiredConfiguration(conf, ref.NameAttr)))
+			}
+		}
+		walk(file, tpd.ProjectType, conf)
+
+		c.record("root", "directory of "+file, c.Root)
+		for key, v := range map[string]interface{}{"name": tpd.ProjectName, "sources": c.Sources, "imports": c.Imports, "defines": c.Defines} {
+			if !reflect.ValueOf(v).IsZero() {
+				c.record(key, file, v)
+			}
+		}
+
+		log.Debugf("project: using titan project %s\n", file)
+		return nil
+	}
+}
+
+func readTitanProject(file string) (*titan.TopLevelProjectType, error) {
+	b, err := fs.Content(file)
+	if err != nil {
+		return nil, err
+	}
+	var tpd titan.TopLevelProjectType
+	if err := xml.Unmarshal(b, &tpd); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if tpd.ProjectType == nil {
+		tpd.ProjectType = &titan.ProjectType{}
+	}
+	return &tpd, nil
+}
+
+// titanSources returns the TTCN-3 source files and directories of a Titan
+// project. Resources excluded from build by the configuration with the given
+// name are omitted. Directories containing excluded files are replaced by
+// their remaining files. An empty name selects the active configuration.
+func titanSources(file string, p *titan.ProjectType, conf string) []string {
+	excluded := titanExcluded(file, p, conf)
+	isExcluded := func(rel string) bool {
+		for rel = path.Clean(rel); rel != "." && rel != "/"; rel = path.Dir(rel) {
+			if excluded[rel] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var srcs []string
+	if p.Folders != nil {
+		for _, r := range p.Folders.FolderResource {
+			root := titanResource(file, p, r)
+			if root == "" || isExcluded(r.ProjectRelativePathAttr) {
+				continue
+			}
+			for _, dir := range fs.FindTTCN3DirectoriesRecursive(root) {
+				rel := r.ProjectRelativePathAttr
+				if sub, err := filepath.Rel(root, dir); err == nil {
+					rel = path.Join(rel, filepath.ToSlash(sub))
+				}
+				if isExcluded(rel) {
+					continue
+				}
+				files := fs.FindTTCN3Files(dir)
+				var kept []string
+				for _, f := range files {
+					if !isExcluded(path.Join(rel, filepath.Base(f))) {
+						kept = append(kept, f)
+					}
+				}
+				if len(kept) == len(files) {
+					srcs = append(srcs, dir)
+				} else {
+					srcs = append(srcs, kept...)
+				}
+			}
+		}
+	}
+	if p.Files != nil {
+		for _, r := range p.Files.FileResource {
+			if src := titanResource(file, p, r); src != "" && fs.HasTTCN3Extension(src) && !isExcluded(r.ProjectRelativePathAttr) {
+				srcs = append(srcs, src)
+			}
+		}
+	}
+	return srcs
+}
+
+// titanExcluded returns the project relative paths of the resources, which
+// the configuration with the given name excludes from build. An empty name
+// selects the active configuration.
+func titanExcluded(file string, p *titan.ProjectType, name string) map[string]bool {
+	if name == "" {
+		name = p.ActiveConfiguration
+	}
+	b, err := fs.Content(file)
+	if err != nil {
+		return nil
+	}
+	var r titan.ProjectResources
+	if err := xml.Unmarshal(b, &r); err != nil {
+		return nil
+	}
+	excluded := make(map[string]bool)
+	for _, c := range r.Configurations.Configuration {
+		if c.NameAttr != name {
+			continue
+		}
+		for _, f := range c.FolderProperties.FolderResource {
+			if f.FolderProperties != nil && f.FolderProperties.ExcludeFromBuild {
+				excluded[path.Clean(f.FolderPath)] = true
+			}
+		}
+		for _, f := range c.FileProperties.FileResource {
+			if f.FileProperties != nil && f.FileProperties.ExcludeFromBuild {
+				excluded[path.Clean(f.FilePath)] = true
+			}
+		}
+	}
+	return excluded
+}
+
+// titanResource returns the path of a resource.
+func titanResource(file string, p *titan.ProjectType, r *titan.ResourceType) string {
+	switch {
+	case r.RelativeURIAttr != "":
+		return titanPath(file, p, r.RelativeURIAttr)
+	case r.RawURIAttr != "":
+		return titanPath(file, p, r.RawURIAttr)
+	default:
+		return titanPath(file, p, r.ProjectRelativePathAttr)
+	}
+}
+
+// titanReference returns the path of the descriptor file of a referenced
+// project or an empty string if it could not be found.
+func titanReference(file string, p *titan.ProjectType, ref *titan.ReferencedProject) string {
+	var candidates []string
+	if ref.ProjectLocationURIAttr != "" {
+		candidates = append(candidates, titanPath(file, p, ref.ProjectLocationURIAttr))
+	}
+	tpdName := ref.TpdNameAttr
+	if tpdName == "" {
+		tpdName = ref.NameAttr + TitanProjectExt
+	}
+	dir := filepath.Dir(file)
+	candidates = append(candidates,
+		filepath.Join(dir, tpdName),
+		filepath.Join(dir, "..", ref.NameAttr, tpdName),
+	)
+	for _, path := range candidates {
+		if fs.IsRegular(path) {
+			return filepath.Clean(path)
+		}
+	}
+	return ""
+}
+
+var parentLoc = regexp.MustCompile(`^PARENT-(\d+)-PROJECT_LOC(/|$)`)
+
+// titanPath resolves Eclipse URIs and path variables. Relative paths are
+// relative to the directory of the project descriptor.
+func titanPath(file string, p *titan.ProjectType, uri string) string {
+	dir := filepath.Dir(file)
+	uri = strings.TrimPrefix(uri, "file:")
+	if strings.HasPrefix(uri, "//") {
+		uri = strings.TrimPrefix(uri, "//")
+	}
+
+	if m := parentLoc.FindStringSubmatch(uri); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		base := dir
+		for i := 0; i < n; i++ {
+			base = filepath.Join(base, "..")
+		}
+		return filepath.Join(base, uri[len(m[0]):])
+	}
+
+	// The first path element might be a path variable.
+	name, rest, _ := strings.Cut(uri, "/")
+	if name == "PROJECT_LOC" {
+		return filepath.Join(dir, rest)
+	}
+	if v, ok := titanPathVariable(p, name); ok {
+		return fs.Real(dir, filepath.Join(v, rest))
+	}
+	return fs.Real(dir, uri)
+}
+
+// titanPathVariable returns the value of a path variable. Environment
+// variables overwrite path variables defined in the project descriptor.
+func titanPathVariable(p *titan.ProjectType, name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	if v, ok := os.LookupEnv(name); ok {
+		return v, true
+	}
+	if p.PathVariables != nil {
+		for _, v := range p.PathVariables.PathVariable {
+			if v.NameAttr == name {
+				return v.ValueAttr, true
+			}
+		}
+	}
+	return "", false
+}
+
+// titanConfiguration returns the build configuration with the given name.
+// An empty name selects the active configuration.
+func titanConfiguration(p *titan.ProjectType, name string) *titan.NamedConfigurationType {
+	if name == "" {
+		name = p.ActiveConfiguration
+	}
+	if p.Configurations == nil {
+		return nil
+	}
+	for _, c := range p.Configurations.Configuration {
+		if c.NameAttr == name && c.ConfigurationType != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// requiredConfiguration returns the build configuration, which the
+// configuration conf requires for the referenced project.
+func requiredConfiguration(conf *titan.NamedConfigurationType, project string) string {
+	if conf == nil || conf.ProjectProperties == nil || conf.ProjectProperties.ConfigurationRequirements == nil {
+		return ""
+	}
+	for _, r := range conf.ProjectProperties.ConfigurationRequirements.ConfigurationRequirement {
+		if r.ProjectName == project {
+			if r.RequiredConfiguration != "" {
+				return r.RequiredConfiguration
+			}
+			return r.RerquiredConfiguration
+		}
+	}
+	return ""
+}
+
+// ExportTitan generates Eclipse Titan project descriptors and a Makefile
+// fragment for the project. The returned map contains the content of the
+// files by file name. Resources are referenced relative to dir, where the
+// files are expected to be written to.
+//
+// Every import directory becomes a referenced library project. The Makefile
+// fragment (<name>.mk) lists the modules of the project and all its imports.
+func ExportTitan(c *Config, dir string) (map[string][]byte, error) {
+	srcs := sourceFiles(c.Sources...)
+	files := make(map[string][]byte)
+	main := newTitanProject(c.Name, dir, srcs)
+
+	if len(c.Defines) > 0 {
+		main.ProjectType.Configurations.Configuration[0].ProjectProperties.MakefileSettings.TTCN3preprocessorDefines = &titan.TTCN3preprocessorDefines{
+			ListItem: c.Defines,
+		}
+	}
+
+	modules := append([]string(nil), srcs...)
+	for _, imp := range c.Imports {
+		name, err := NameFromURI(imp)
+		if err != nil {
+			return nil, err
+		}
+		libs := sourceFiles(imp)
+		if len(libs) == 0 {
+			continue
+		}
+
+		// Directories with equal names get a unique suffix.
+		for i := 2; files[name+TitanProjectExt] != nil || name == c.Name; i++ {
+			name = fmt.Sprintf("%s_%d", strings.TrimRight(name, "_0123456789"), i)
+		}
+
+		lib := newTitanProject(name, dir, libs)
+		b, err := marshalTitanProject(lib)
+		if err != nil {
+			return nil, err
+		}
+		files[name+TitanProjectExt] = b
+		main.ReferencedProjects.ReferencedProject = append(main.ReferencedProjects.ReferencedProject, &titan.ReferencedProject{
+			NameAttr:               name,
+			ProjectLocationURIAttr: name + TitanProjectExt,
+		})
+		modules = append(modules, libs...)
+	}
+	if len(main.ReferencedProjects.ReferencedProject) == 0 {
+		main.ReferencedProjects = nil
+	}
+
+	b, err := marshalTitanProject(main)
+	if err != nil {
+		return nil, err
+	}
+	files[c.Name+TitanProjectExt] = b
+	files[c.Name+".mk"] = titanMakefile(c, dir, modules)
+	return files, nil
+}
+
+func newTitanProject(name string, dir string, srcs []string) *titan.TopLevelProjectType {
+	p := &titan.TopLevelProjectType{
+		VersionAttr: 1.0,
+		ProjectType: &titan.ProjectType{
+			ProjectName:         name,
+			ReferencedProjects:  &titan.ReferencedProjects{},
+			Files:               &titan.Files{},
+			ActiveConfiguration: "Default",
+			Configurations: &titan.Configurations{
+				Configuration: []*titan.NamedConfigurationType{{
+					NameAttr: "Default",
+					ConfigurationType: &titan.ConfigurationType{
+						ProjectProperties: &titan.ProjectProperties{
+							MakefileSettings: &titan.MakefileSettings{
+								GenerateMakefile: true,
+								GNUMake:          true,
+								DefaultTarget:    "executable",
+								TargetExecutable: "bin/" + name,
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+	// Project relative paths must be unique, hence they are relative to
+	// the common directory of all sources.
+	root := commonDir(srcs)
+	for _, src := range srcs {
+		r := &titan.ResourceType{ProjectRelativePathAttr: filepath.Base(src)}
+		if rel := titanRel(root, src); rel != "" {
+			r.ProjectRelativePathAttr = rel
+		}
+		if rel := titanRel(dir, src); rel != "" {
+			r.RelativeURIAttr = rel
+		} else {
+			r.RawURIAttr = "file:" + filepath.ToSlash(src)
+		}
+		p.Files.FileResource = append(p.Files.FileResource, r)
+	}
+	return p
+}
+
+func marshalTitanProject(p *titan.TopLevelProjectType) ([]byte, error) {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	enc := xml.NewEncoder(&sb)
+	enc.Indent("", "  ")
+	root := xml.StartElement{Name: xml.Name{Local: "TITAN_Project_File_Information"}}
+	if err := enc.EncodeElement(p, root); err != nil {
+		return nil, err
+	}
+	sb.WriteString("\n")
+	return []byte(sb.String()), nil
+}
+
+// commonDir returns the longest common directory of the given files.
+func commonDir(files []string) string {
+	if len(files) == 0 {
+		return ""
+	}
+	dir := filepath.Dir(absPath(files[0]))
+	for _, f := range files[1:] {
+		f = absPath(f)
+		for !strings.HasPrefix(f, dir+string(filepath.Separator)) && dir != filepath.Dir(dir) {
+			dir = filepath.Dir(dir)
+		}
+	}
+	return dir
+}
+
+// titanMakefile returns a Makefile fragment with the variables used by
+// Makefiles generated by Titan's makefilegen.
+func titanMakefile(c *Config, dir string, modules []string) []byte {
+	var (
+		sb            strings.Builder
+		ttcn, pp, asn []string
+	)
+	for _, m := range modules {
+		if rel := titanRel(dir, m); rel != "" {
+			m = rel
+		}
+		switch {
+		case fs.HasASN1Extension(m):
+			asn = append(asn, m)
+		case filepath.Ext(m) == ".ttcnpp":
+			pp = append(pp, m)
+		default:
+			ttcn = append(ttcn, m)
+		}
+	}
+
+	list := func(name string, values []string) {
+		fmt.Fprintf(&sb, "%s =", name)
+		for _, v := range values {
+			fmt.Fprintf(&sb, " \\\n\t%s", v)
+		}
+		sb.WriteString("\n\n")
+	}
+
+	fmt.Fprintf(&sb, "# Titan Makefile fragment for project %s, generated by ntt.\n\n", c.Name)
+	list("TTCN3_MODULES", ttcn)
+	list("TTCN3_PP_MODULES", pp)
+	list("ASN1_MODULES", asn)
+	var defines []string
+	for _, d := range c.Defines {
+		defines = append(defines, "-D"+d)
+	}
+	list("CPPFLAGS_TTCN3", defines)
+	fmt.Fprintf(&sb, "EXECUTABLE = %s\n", c.Name)
+	return []byte(sb.String())
+}
+
+// titanRel returns path relative to dir using forward slashes or an empty
+// string if there is no relative path.
+func titanRel(dir, path string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(absDir, abs)
+	if err != nil {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
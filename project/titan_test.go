@@ -0,0 +1,215 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWithTitanProject(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TITAN_LIBS", filepath.Join(dir, "libs"))
+	writeFiles(t, dir, map[string]string{
+		"suite/Suite.tpd": `<?xml version="1.0" encoding="UTF-8"?>
+<TITAN_Project_File_Information version="1.0">
+  <ProjectName>MySuite</ProjectName>
+  <ReferencedProjects>
+    <ReferencedProject name="Lib" projectLocationURI="../lib/Lib.tpd"/>
+    <ReferencedProject name="Missing"/>
+  </ReferencedProjects>
+  <Folders>
+    <FolderResource projectRelativePath="src" relativeURI="src"/>
+  </Folders>
+  <Files>
+    <FileResource projectRelativePath="main.ttcn" relativeURI="main.ttcn"/>
+    <FileResource projectRelativePath="excluded.ttcn" relativeURI="excluded.ttcn"/>
+    <FileResource projectRelativePath="codec.cc" relativeURI="codec.cc"/>
+    <FileResource projectRelativePath="ext.ttcn" rawURI="TITAN_LIBS/ext.ttcn"/>
+  </Files>
+  <ActiveConfiguration>Debug</ActiveConfiguration>
+  <Configurations>
+    <Configuration name="Debug">
+      <ProjectProperties>
+        <MakefileSettings>
+          <TTCN3preprocessorDefines>
+            <listItem>DEBUG</listItem>
+            <listItem>LEVEL=2</listItem>
+          </TTCN3preprocessorDefines>
+        </MakefileSettings>
+        <ConfigurationRequirements>
+          <configurationRequirement>
+            <projectName>Lib</projectName>
+            <requiredConfiguration>Other</requiredConfiguration>
+          </configurationRequirement>
+        </ConfigurationRequirements>
+      </ProjectProperties>
+      <FolderProperties>
+        <FolderResource>
+          <FolderPath>src/old</FolderPath>
+          <FolderProperties>
+            <ExcludeFromBuild>true</ExcludeFromBuild>
+          </FolderProperties>
+        </FolderResource>
+      </FolderProperties>
+      <FileProperties>
+        <FileResource>
+          <FilePath>src/sub/skip.ttcn</FilePath>
+          <FileProperties>
+            <ExcludeFromBuild>true</ExcludeFromBuild>
+          </FileProperties>
+        </FileResource>
+        <FileResource>
+          <FilePath>excluded.ttcn</FilePath>
+          <FileProperties>
+            <ExcludeFromBuild>true</ExcludeFromBuild>
+          </FileProperties>
+        </FileResource>
+      </FileProperties>
+    </Configuration>
+    <Configuration name="Release">
+      <ProjectProperties>
+        <MakefileSettings>
+          <TTCN3preprocessorDefines>
+            <listItem>RELEASE</listItem>
+          </TTCN3preprocessorDefines>
+        </MakefileSettings>
+      </ProjectProperties>
+      <FileProperties>
+        <FileResource>
+          <FilePath>main.ttcn</FilePath>
+          <FileProperties>
+            <ExcludeFromBuild>true</ExcludeFromBuild>
+          </FileProperties>
+        </FileResource>
+      </FileProperties>
+    </Configuration>
+  </Configurations>
+</TITAN_Project_File_Information>`,
+		"suite/src/a.ttcn":        `module a {}`,
+		"suite/src/sub/b.ttcn":    `module b {}`,
+		"suite/src/sub/skip.ttcn": `module skip {}`,
+		"suite/src/old/o.ttcn":    `module o {}`,
+		"suite/main.ttcn":         `module main {}`,
+		"suite/excluded.ttcn":     `module excluded {}`,
+		"suite/codec.cc":          ``,
+		"libs/ext.ttcn":           `module ext {}`,
+		"lib/Lib.tpd": `<TITAN_Project_File_Information version="1.0">
+  <ProjectName>Lib</ProjectName>
+  <ReferencedProjects>
+    <ReferencedProject name="MySuite" projectLocationURI="PARENT-1-PROJECT_LOC/suite/Suite.tpd"/>
+  </ReferencedProjects>
+  <Folders>
+    <FolderResource projectRelativePath="common" relativeURI="common"/>
+  </Folders>
+</TITAN_Project_File_Information>`,
+		"lib/common/c.ttcn": `module c {}`,
+	})
+
+	c := &Config{}
+	err := WithTitanProject(filepath.Join(dir, "suite/Suite.tpd"))(c)
+	assert.Nil(t, err)
+	assert.Equal(t, "MySuite", c.Name)
+	assert.Equal(t, filepath.Join(dir, "suite"), c.Root)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "suite/src"),
+		filepath.Join(dir, "suite/src/sub/b.ttcn"),
+		filepath.Join(dir, "suite/main.ttcn"),
+		filepath.Join(dir, "libs/ext.ttcn"),
+	}, c.Sources)
+	assert.Equal(t, []string{filepath.Join(dir, "lib/common")}, c.Imports)
+	assert.Equal(t, []string{"DEBUG", "LEVEL=2"}, c.Defines)
+}
+
+func TestOpenTitanProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Suite.tpd": `<TITAN_Project_File_Information version="1.0">
+  <ProjectName>Suite</ProjectName>
+  <Files><FileResource projectRelativePath="a.ttcn" relativeURI="a.ttcn"/></Files>
+</TITAN_Project_File_Information>`,
+		"a.ttcn": `module a {}`,
+		"b.ttcn": `module b {}`,
+	})
+
+	c, err := Open(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, "Suite", c.Name)
+	assert.Equal(t, filepath.Join(dir, "Suite.tpd"), c.TitanProjectFile)
+	assert.Equal(t, []string{filepath.Join(dir, "a.ttcn")}, c.Sources)
+}
+
+func TestExportTitan(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"suite/a.ttcn":     `module a {}`,
+		"suite/b.ttcnpp":   `module b {}`,
+		"suite/e.asn":      `E DEFINITIONS ::= BEGIN END`,
+		"suite/sub/a.ttcn": `module sub_a {}`,
+		"common/c.ttcn3":   `module c {}`,
+		"common/d/d.ttcn":  `module d {}`,
+	})
+
+	c := &Config{}
+	c.Name = "suite"
+	c.Sources = []string{filepath.Join(dir, "suite"), filepath.Join(dir, "suite/sub/a.ttcn")}
+	c.Imports = []string{filepath.Join(dir, "common"), filepath.Join(dir, "common/d")}
+	c.Defines = []string{"DEBUG"}
+
+	out := filepath.Join(dir, "out")
+	files, err := ExportTitan(c, out)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(files))
+	assert.Contains(t, files, "suite.tpd")
+	assert.Contains(t, files, "common.tpd")
+	assert.Contains(t, files, "d.tpd")
+	assert.Equal(t, `# Titan Makefile fragment for project suite, generated by ntt.
+
+TTCN3_MODULES = \
+	../suite/a.ttcn \
+	../suite/sub/a.ttcn \
+	../common/c.ttcn3 \
+	../common/d/d.ttcn
+
+TTCN3_PP_MODULES = \
+	../suite/b.ttcnpp
+
+ASN1_MODULES = \
+	../suite/e.asn
+
+CPPFLAGS_TTCN3 = \
+	-DDEBUG
+
+EXECUTABLE = suite
+`, string(files["suite.mk"]))
+
+	tpd := string(files["suite.tpd"])
+	assert.Contains(t, tpd, `projectRelativePath="sub/a.ttcn"`)
+	assert.Contains(t, tpd, `projectRelativePath="e.asn"`)
+	assert.NotContains(t, tpd, "<dynamicLinking>")
+
+	// Reading the exported project must result in the same configuration.
+	for name, b := range files {
+		writeFiles(t, out, map[string]string{name: string(b)})
+	}
+	c2 := &Config{}
+	assert.Nil(t, WithTitanProject(filepath.Join(out, "suite.tpd"))(c2))
+	assert.Equal(t, "suite", c2.Name)
+	assert.Equal(t, []string{filepath.Join(dir, "suite/a.ttcn"), filepath.Join(dir, "suite/b.ttcnpp"), filepath.Join(dir, "suite/sub/a.ttcn")}, c2.Sources)
+	assert.Equal(t, []string{filepath.Join(dir, "common/c.ttcn3"), filepath.Join(dir, "common/d/d.ttcn")}, c2.Imports)
+	assert.Equal(t, []string{"DEBUG"}, c2.Defines)
+}
@@ -0,0 +1,85 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// A Workspace is a set of test suites processed together, for example all
+// test suites listed in an index file (ttcn3_suites.json).
+type Workspace struct {
+	Suites []*Config
+}
+
+// A Group is a test suite and the files of the test suite.
+type Group struct {
+	Suite *Config
+	Files []string
+}
+
+// NewWorkspace opens the configuration of every given test suite. Suites with
+// the same root directory are opened only once. Suites which cannot be
+// configured are skipped and their errors returned along with the workspace.
+func NewWorkspace(suites ...Suite) (*Workspace, error) {
+	var (
+		w       Workspace
+		errs    *multierror.Error
+		visited = make(map[string]bool)
+	)
+	for _, s := range suites {
+		if visited[s.RootDir] {
+			continue
+		}
+		visited[s.RootDir] = true
+
+		conf, err := NewConfig(
+			AutomaticRoot(s.RootDir),
+			AutomaticEnv(),
+			WithSourceDir(s.SourceDir),
+			WithDefaults(),
+		)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("%s: %w", s.RootDir, err))
+			continue
+		}
+		w.Suites = append(w.Suites, conf)
+	}
+	return &w, errs.ErrorOrNil()
+}
+
+// Groups returns the files of every test suite as returned by the files
+// function, for example Files. A file shared by several test suites, like a
+// common import directory, is listed only in the group of the first suite.
+func (w *Workspace) Groups(files func(*Config) ([]string, error)) ([]Group, error) {
+	var (
+		groups []Group
+		errs   *multierror.Error
+		seen   = make(map[string]bool)
+	)
+	for _, c := range w.Suites {
+		list, err := files(c)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		g := Group{Suite: c}
+		for _, f := range list {
+			if !seen[f] {
+				seen[f] = true
+				g.Files = append(g.Files, f)
+			}
+		}
+		groups = append(groups, g)
+	}
+	return groups, errs.ErrorOrNil()
+}
+
+// Files returns the files of all test suites without duplicates.
+func (w *Workspace) Files() ([]string, error) {
+	groups, err := w.Groups(Files)
+	var files []string
+	for _, g := range groups {
+		files = append(files, g.Files...)
+	}
+	return files, err
+}
@@ -0,0 +1,44 @@
+package project
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a/package.yml":      "name: a\nsources: [a.ttcn3]\nimports: [../common]\n",
+		"a/a.ttcn3":          "module a {}",
+		"b/package.yml":      "name: b\nsources: [b.ttcn3]\nimports: [../common]\n",
+		"b/b.ttcn3":          "module b {}",
+		"common/c.ttcn3":     "module c {}",
+		"broken/package.yml": "sources: 1\n",
+	})
+
+	w, err := NewWorkspace(
+		Suite{RootDir: filepath.Join(dir, "a")},
+		Suite{RootDir: filepath.Join(dir, "b")},
+		Suite{RootDir: filepath.Join(dir, "a")},
+		Suite{RootDir: filepath.Join(dir, "broken")},
+	)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, len(w.Suites))
+
+	groups, _ := w.Groups(Files)
+	var actual [][]string
+	for _, g := range groups {
+		var files []string
+		for _, f := range g.Files {
+			rel, _ := filepath.Rel(dir, f)
+			files = append(files, rel)
+		}
+		actual = append(actual, append([]string{g.Suite.Name}, files...))
+	}
+	assert.Equal(t, [][]string{
+		{"a", "a/a.ttcn3", "common/c.ttcn3"},
+		{"b", "b/b.ttcn3"},
+	}, actual)
+}
@@ -34,9 +34,32 @@ information such as a list of tests which did not pass, average run times, CPU
 load, etc.
 Command line options '--json' and '--junit' show similar output, but with JSON
 or JUNIT formatting.
+Command line option '--html' outputs a single HTML page without external
+assets. It contains verdict charts, a duration histogram and a system load
+timeline, and lists the tests per module with reason files and links to their
+source locations.
 
 Use environment variable 'NTT_COLORS=never' to disable colors.
 
+History
+-------
+
+Command line option '--record' appends the sessions of the latest test run to
+the history file test_history.jsonl, located next to the results file. Each
+session is recorded once, together with the current git commit and the time
+the session started.
+
+Command line options '--compare' and '--since' compare the latest test run
+with the history and report newly failing, newly passing and newly flaky tests
+and duration regressions:
+
+	ntt report --compare             # compare with the previous session
+	ntt report --compare=ID|COMMIT   # compare with a specific session
+	ntt report --since=7d            # compare with all sessions of the last week
+
+A test regressed if its average duration grew by more than 20% and at least
+one second.
+
 Templating
 ----------
 
@@ -72,6 +95,7 @@ Available Objects
   .RunSlice.Last:      Last test run
   .RunSlice.Longest:   Longest test run
   .RunSlice.NotPassed: A slice of tests without 'pass' verdict
+  .RunSlice.Quarantined: A slice of failed tests from the quarantine list
   .RunSlice.Result:    Final result (PASSED, FAILED, UNSTABLE, NOEXEC)
   .RunSlice.Shortest:  Shortest test run
   .RunSlice.Total:     Sum of all test run durations
@@ -107,6 +131,7 @@ Additional commands
   bold:     output ANSI sequences for bold text
   off:      output ANSI sequences to reset attributes
   colorize: colorize output
+  add:      add two integers
   join:     join input with a separator
   json:     encode input using JSON format
   min:      returns the minimum of a float slice
@@ -133,6 +158,7 @@ JSON template:
 
 	useJSON  = false
 	useJUnit = false
+	useHTML  = false
 
 	templateText = ""
 )
@@ -145,7 +171,8 @@ const (
 {{end}}{{end}}
 {{len .Tests}} test cases took {{bold}}{{.Tests.Duration}}{{off}} to execute (total runs: {{len .Runs}}
 {{- with .Tests.Failed}}, {{red}}not passed: {{len .}}{{off}}{{end}}
-{{- with .Tests.Unstable}}, {{orange}}unstable: {{len .}}{{off}}{{end}})
+{{- with .Tests.Unstable}}, {{orange}}unstable: {{len .}}{{off}}{{end}}
+{{- with .Tests.Quarantined}}, {{orange}}quarantined: {{len .}}{{off}}{{end}})
 {{bold}}==============================================================================={{off}}
 
 {{ printf "%s (±%s)" .Tests.Average .Tests.Deviation | printf "Average  : %-30s CPU cores      : " }}{{printf "%d" .Cores}}
@@ -161,9 +188,10 @@ const (
 	JUnitTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 <testsuites>{{range .Modules}}
 
-<testsuite name="{{.Name}}" tests="{{len .FixedTests}}" failures="{{len .FixedTests.Failed}}" skipped="{{len .FixedTests.Skipped}}" errors="" time="{{.FixedTests.Total.Seconds}}">
+<testsuite name="{{.Name}}" tests="{{len .FixedTests}}" failures="{{len .FixedTests.Failed}}" skipped="{{len .FixedTests.Skipped | add (len .FixedTests.Quarantined)}}" errors="" time="{{.FixedTests.Total.Seconds}}">
 {{range .FixedTests}}<testcase name="{{.Testcase}}" time="{{.Duration.Seconds}}">
 {{if eq .Verdict "skipped"}}  <skipped type="skipped" message="Verdict: skipped">{{with .Reason}}{{. | html }}{{end}}</skipped>
+{{else if eq .Verdict "quarantined"}}  <skipped type="quarantined" message="Verdict: quarantined">{{with .Reason}}{{. | html }}{{end}}</skipped>
 {{else if and (ne .Verdict "unstable") (ne .Verdict "pass")}}  <failure>Verdict: {{.Verdict}} {{with .Reason}}({{. | html }}){{end}}
 {{range .ReasonFiles}}{{.Name}}: {{.Content}}{{end}}
   </failure>
@@ -212,12 +240,32 @@ func report(cmd *cobra.Command, args []string) error {
 		templateText = JSONTemplate
 	case useJUnit:
 		templateText = JUnitTemplate
+	case useHTML:
+		return writeHTMLReport(os.Stdout)
 	}
 
 	if templateText == "" {
 		templateText = SummaryTemplate
 	}
 
+	if recordHistory || compareRef != "" || sinceTime != "" {
+		if compareRef != "" && sinceTime != "" {
+			return fmt.Errorf("--compare and --since are mutually exclusive")
+		}
+		db, err := latestResults()
+		if err != nil {
+			return err
+		}
+		if recordHistory {
+			if err := recordSessions(db); err != nil {
+				return err
+			}
+		}
+		if compareRef != "" || sinceTime != "" {
+			return compareHistory(os.Stdout, db)
+		}
+	}
+
 	return ReportTemplate(os.Stdout, templateText)
 }
 
@@ -296,6 +344,9 @@ var funcMap = template.FuncMap{
 			}
 		})
 	},
+	"add": func(a, b int) int {
+		return a + b
+	},
 	"join": func(sep string, v interface{}) string {
 		return strings.Join(v.([]string), sep)
 	},
@@ -351,7 +402,12 @@ var funcMap = template.FuncMap{
 func init() {
 	ReportCommand.PersistentFlags().BoolVarP(&useJSON, "json", "", false, "output report in JSON format")
 	ReportCommand.PersistentFlags().BoolVarP(&useJUnit, "junit", "", false, "output report in Junit format")
+	ReportCommand.PersistentFlags().BoolVarP(&useHTML, "html", "", false, "output report as self-contained HTML page")
 	ReportCommand.PersistentFlags().StringVarP(&templateText, "template", "t", "", "output report with custom template")
+	ReportCommand.PersistentFlags().BoolVarP(&recordHistory, "record", "", false, "append latest test run to the history")
+	ReportCommand.PersistentFlags().StringVarP(&sinceTime, "since", "", "", "compare with sessions recorded since `TIME`")
+	ReportCommand.PersistentFlags().StringVarP(&compareRef, "compare", "", "", "compare with session or commit `REF` (default: previous)")
+	ReportCommand.PersistentFlags().Lookup("compare").NoOptDefVal = "previous"
 }
 
 type Report struct {
@@ -362,7 +418,7 @@ type Report struct {
 }
 
 func NewReport(suite *project.Config) (*Report, error) {
-	db, err := results.Latest()
+	db, err := latestResults()
 	if err != nil {
 		return nil, err
 	}
@@ -376,12 +432,25 @@ func NewReport(suite *project.Config) (*Report, error) {
 
 	if db != nil {
 		r.db = *db
-		r.Collection = *NewCollection(r.Name, db.Runs()...)
+		r.Collection = *NewCollection(r.Name, quarantine(suite, db.Runs())...)
 	}
 
 	return &r, nil
 }
 
+// quarantine changes the verdict of failed runs of quarantined tests to
+// "quarantined". The original verdict is prepended to the reason.
+func quarantine(suite *project.Config, runs []results.Run) []results.Run {
+	for i, r := range runs {
+		if r.Verdict == "pass" || r.Verdict == "skipped" || !suite.IsQuarantined(r.Name) {
+			continue
+		}
+		runs[i].Reason = strings.TrimSuffix("verdict "+r.Verdict+": "+r.Reason, ": ")
+		runs[i].Verdict = "quarantined"
+	}
+	return runs
+}
+
 func (r *Report) Getenv(s string) string {
 	if s, ok := env.LookupEnv(s); ok {
 		return s
@@ -566,7 +635,11 @@ func (rs RunSlice) Skipped() []Run {
 }
 
 func (rs RunSlice) Failed() []Run {
-	return rs.filter(func(s string) bool { return s != "pass" && s != "unstable" })
+	return rs.filter(func(s string) bool { return s != "pass" && s != "unstable" && s != "quarantined" })
+}
+
+func (rs RunSlice) Quarantined() []Run {
+	return rs.filter(func(s string) bool { return s == "quarantined" })
 }
 
 func (rs RunSlice) Unstable() []Run {
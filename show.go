@@ -16,6 +16,7 @@ import (
 	"github.com/nokia/ntt/internal/yaml"
 	"github.com/nokia/ntt/project"
 	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/doc"
 	"github.com/nokia/ntt/ttcn3/syntax"
 	"github.com/spf13/cobra"
 )
@@ -36,6 +37,8 @@ func show(cmd *cobra.Command, args []string) error {
 	r.Files, r.err = project.Files(Project)
 
 	switch {
+	case explain:
+		return printExplanations(Project, keys)
 	case outputJSON:
 		return printJSON(&r, keys)
 	case ShSetup:
@@ -104,8 +107,11 @@ func printJSON(report *ConfigReport, keys []string) error {
 					default:
 						return true
 					}
-					name := tree.QualifiedName(n)
-					tc, err := report.TestConfigs(name, presets...)
+					test := project.Test{
+						Name: tree.QualifiedName(n),
+						Tags: doc.FindAllTags(syntax.Doc(n)),
+					}
+					tc, err := report.TestConfigsFor(test, presets...)
 					if err != nil {
 						log.Debugf("implementation error: %s\n", err)
 					}
@@ -266,6 +272,43 @@ func printKeyValues(c *project.Config, keys []string) error {
 	return nil
 }
 
+// printExplanations prints the resolved value, the origin and the overridden
+// origins of each configuration key and variable. If keys are given, only
+// those keys are explained.
+func printExplanations(c *project.Config, keys []string) error {
+	var list []project.Explanation
+	for _, e := range c.Explain() {
+		if len(keys) == 0 || contains(keys, e.Key) {
+			list = append(list, e)
+		}
+	}
+
+	if outputJSON {
+		if list == nil {
+			list = []project.Explanation{}
+		}
+		b, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	for _, e := range list {
+		fmt.Printf("%s = %q\n", e.Key, e.Value)
+		fmt.Printf("    from %s", e.Origin.Source)
+		if e.Origin.Value != e.Value {
+			fmt.Printf(": %q", e.Origin.Value)
+		}
+		fmt.Println()
+		for _, o := range e.Overridden {
+			fmt.Printf("    overrides %s: %q\n", o.Source, o.Value)
+		}
+	}
+	return nil
+}
+
 // splitArgs splits an argument list at pos. Pos is usually the position of '--'
 // (see cobra.Command.ArgsLenAtDash).
 //
@@ -291,3 +334,12 @@ func (r *ConfigReport) Err() string {
 	}
 	return ""
 }
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
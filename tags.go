@@ -30,11 +30,16 @@ object).`,
 )
 
 func tags(cmd *cobra.Command, args []string) error {
-	files, err := project.Files(Project)
+	groups, err := suiteGroups(project.Files)
 	if err != nil {
 		return err
 	}
 
+	var files []string
+	for _, g := range groups {
+		files = append(files, g.Files...)
+	}
+
 	var wg sync.WaitGroup
 	wg.Add(len(files))
 
@@ -0,0 +1,151 @@
+// Package extract computes the minimal subset of TTCN-3 definitions required
+// by a set of definitions, for example a single testcase.
+package extract
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/printer"
+	"github.com/nokia/ntt/ttcn3/syntax"
+)
+
+// Def is a module definition.
+type Def struct {
+	*syntax.ModuleDef
+	Tree *ttcn3.Tree
+}
+
+// Module is a module stripped down to the required definitions.
+type Module struct {
+	Name string
+
+	// Imports is the sorted list of modules imported.
+	Imports []string
+
+	// Defs are the required definitions in source order.
+	Defs []Def
+}
+
+// Closure returns the definitions required by roots, including the module
+// definitions of roots themselves. Definitions are resolved using db.
+//
+// Closure is conservative: if a reference resolves to multiple definitions,
+// all of them are included.
+func Closure(db *ttcn3.DB, roots ...*ttcn3.Node) []*Module {
+	var (
+		seen = make(map[*syntax.ModuleDef]bool)
+		q    []Def
+	)
+
+	add := func(n *ttcn3.Node) {
+		if d, ok := moduleDef(n); ok && !seen[d.ModuleDef] {
+			seen[d.ModuleDef] = true
+			q = append(q, d)
+		}
+	}
+
+	for _, r := range roots {
+		add(r)
+	}
+
+	var defs []Def
+	for len(q) > 0 {
+		d := q[0]
+		q = q[1:]
+		defs = append(defs, d)
+		for _, n := range ttcn3.References(d.Def) {
+			for _, def := range d.Tree.LookupWithDB(n, db) {
+				add(def)
+			}
+		}
+	}
+	return modules(defs)
+}
+
+// moduleDef returns the module definition enclosing n.
+func moduleDef(n *ttcn3.Node) (Def, bool) {
+	if n == nil || n.Tree == nil {
+		return Def{}, false
+	}
+	if md := n.Tree.ModuleDefOf(n.Node); md != nil {
+		return Def{ModuleDef: md, Tree: n.Tree}, true
+	}
+	return Def{}, false
+}
+
+// modules groups definitions by module.
+func modules(defs []Def) []*Module {
+	mods := make(map[string]*Module)
+	for _, d := range defs {
+		mod := d.Tree.ModuleOf(d.ModuleDef)
+		if mod == nil {
+			continue
+		}
+		name := syntax.Name(mod.Name)
+		if mods[name] == nil {
+			mods[name] = &Module{Name: name}
+		}
+		mods[name].Defs = append(mods[name].Defs, d)
+	}
+
+	var ret []*Module
+	for _, m := range mods {
+		imports := make(map[string]bool)
+		for _, d := range m.Defs {
+			for name := range importsOf(d) {
+				if name != m.Name && mods[name] != nil {
+					imports[name] = true
+				}
+			}
+		}
+		for name := range imports {
+			m.Imports = append(m.Imports, name)
+		}
+		sort.Strings(m.Imports)
+		sort.Slice(m.Defs, func(i, j int) bool {
+			if a, b := m.Defs[i].Tree.Filename(), m.Defs[j].Tree.Filename(); a != b {
+				return a < b
+			}
+			return m.Defs[i].Pos() < m.Defs[j].Pos()
+		})
+		ret = append(ret, m)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// importsOf returns the modules imported by the module defining d.
+func importsOf(d Def) map[string]bool {
+	ret := make(map[string]bool)
+	if mod := d.Tree.ModuleOf(d.ModuleDef); mod != nil {
+		mod.Inspect(func(n syntax.Node) bool {
+			if x, ok := n.(*syntax.ImportDecl); ok {
+				ret[syntax.Name(x.Module)] = true
+				return false
+			}
+			return true
+		})
+	}
+	return ret
+}
+
+// Fprint prints module m as TTCN-3 source. Imports of the module are
+// printed as "import from X all", because explicit import lists might
+// reference definitions which are not part of m. Groups are not preserved.
+func Fprint(w io.Writer, m *Module) error {
+	fmt.Fprintf(w, "module %s {\n", m.Name)
+	for _, name := range m.Imports {
+		fmt.Fprintf(w, "import from %s all;\n", name)
+	}
+	for _, d := range m.Defs {
+		if err := printer.Print(w, d.ModuleDef); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, ";")
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
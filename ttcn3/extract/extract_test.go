@@ -0,0 +1,101 @@
+package extract_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/extract"
+	"github.com/nokia/ntt/ttcn3/syntax"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClosure(t *testing.T) {
+	files := map[string]string{
+		"test://A.ttcn3": `module A {
+			import from B all;
+			type component C { var T v }
+			function f() runs on C return integer { return B.g() + k }
+			function unused() {}
+			testcase tc() runs on C { f(); setverdict(pass) }
+			testcase other() runs on C { unused() }
+			const integer k := 1;
+		}`,
+		"test://B.ttcn3": `module B {
+			import from C all;
+			type record T { integer x }
+			function g() return integer { var R r; return r.y }
+			const integer unused := 2;
+			type enumerated E { e1 }
+		}`,
+		"test://C.ttcn3": `module C {
+			type record R { integer y }
+			template R t := { y := 0 }
+		}`,
+		"test://D.ttcn3": `module D { import from A all }`,
+	}
+
+	var names []string
+	for name, src := range files {
+		fs.SetContent(name, []byte(src))
+		names = append(names, name)
+	}
+	db := &ttcn3.DB{}
+	db.Index(names...)
+
+	tree := ttcn3.ParseFile("test://A.ttcn3")
+	var root *ttcn3.Node
+	for _, tc := range tree.Tests() {
+		if tc.Ident.String() == "tc" {
+			root = tc
+		}
+	}
+
+	actual := make(map[string][]string)
+	for _, m := range extract.Closure(db, root) {
+		for _, d := range m.Defs {
+			actual[m.Name] = append(actual[m.Name], name(d.Def))
+		}
+		assert.NotContains(t, m.Imports, m.Name)
+	}
+	assert.Equal(t, map[string][]string{
+		"A": {"C", "f", "tc", "k"},
+		"B": {"T", "g"},
+		"C": {"R"},
+	}, actual)
+}
+
+func TestFprint(t *testing.T) {
+	fs.SetContent("test://E.ttcn3", []byte(`module E {
+		import from F { const c };
+		private const integer x := c;
+		group G { testcase tc() { if (x > 1) { setverdict(pass) } } }
+	}`))
+	fs.SetContent("test://F.ttcn3", []byte(`module F { const integer c := 1; const integer d := 2 }`))
+	db := &ttcn3.DB{}
+	db.Index("test://E.ttcn3", "test://F.ttcn3")
+
+	tree := ttcn3.ParseFile("test://E.ttcn3")
+	mods := extract.Closure(db, tree.Tests()...)
+	assert.Equal(t, 2, len(mods))
+
+	var sb strings.Builder
+	for _, m := range mods {
+		assert.Nil(t, extract.Fprint(&sb, m))
+	}
+
+	// The result must be valid TTCN-3 again.
+	out := ttcn3.Parse(sb.String())
+	assert.Nil(t, out.Err, sb.String())
+	assert.Contains(t, sb.String(), "import from F all;")
+	assert.Contains(t, sb.String(), "private const integer x")
+	assert.NotContains(t, sb.String(), "d := 2")
+}
+
+func name(n syntax.Node) string {
+	if v, ok := n.(*syntax.ValueDecl); ok {
+		return syntax.Name(v.Decls[0])
+	}
+	return syntax.Name(n)
+}
@@ -30,6 +30,9 @@ func Fprint(w io.Writer, src interface{}) error {
 
 // CanonicalPrinter is a simple formatter that only fixes indentation and
 // various whitespace issues.
+//
+// Optionally, the printer wraps long lists, aligns assignments and normalizes
+// blank lines between module definitions (see NewPrettyPrinter).
 type CanonicalPrinter struct {
 	// UseSpaces controls whether to use spaces instead of tabs for indenting.
 	UseSpaces bool
@@ -41,31 +44,60 @@ type CanonicalPrinter struct {
 	// Indent is the level of indentation at which to start.
 	Indent int
 
+	// LineWidth is the maximum width of a line. Parameter lists, argument
+	// lists and composite literals (e.g. template bodies) exceeding this
+	// width are wrapped, one element per line. Zero disables wrapping.
+	LineWidth int
+
+	// AlignAssignments aligns the ":=" of field assignments in composite
+	// literals, if the fields start on their own lines.
+	AlignAssignments bool
+
+	// BlankLines is the number of blank lines between module definitions.
+	// A negative value keeps the blank lines from the source (at most
+	// one).
+	BlankLines int
+
 	w                io.Writer
+	out              io.Writer
 	lastPos, currPos syntax.Position
 	whiteBuf         string
 	firstToken       bool
 	stack            []syntax.Node
+
+	hints *hints
+
+	// Output tracking used for line wrapping.
+	line, col int
+	pending   int
+	lineFirst int
+	widths    map[int]int
+	tokLine   map[int]int
+	starts    map[int]bool
 }
 
 // NewCanonicalPrinter returns a new printer that formats source code.
 func NewCanonicalPrinter(w io.Writer) *CanonicalPrinter {
 	return &CanonicalPrinter{
-		Indent:   0,
-		TabWidth: 8,
-		w:        w,
+		Indent:     0,
+		TabWidth:   8,
+		BlankLines: -1,
+		w:          w,
 	}
 }
 
-func (p *CanonicalPrinter) Fprint(v interface{}) error {
-	minwidth := p.TabWidth
-	twmode := tabwriter.DiscardEmptyColumns | tabwriter.StripEscape
-	if !p.UseSpaces {
-		minwidth = 0
-		twmode |= tabwriter.TabIndent
-	}
-	p.w = tabwriter.NewWriter(p.w, minwidth, p.TabWidth, 1, ' ', twmode)
+// NewPrettyPrinter returns a new printer, which additionally wraps lines
+// longer than width, aligns assignments in composite literals and separates
+// module definitions by exactly one blank line.
+func NewPrettyPrinter(w io.Writer, width int) *CanonicalPrinter {
+	p := NewCanonicalPrinter(w)
+	p.LineWidth = width
+	p.AlignAssignments = true
+	p.BlankLines = 1
+	return p
+}
 
+func (p *CanonicalPrinter) Fprint(v interface{}) error {
 	b, err := toBytes(v)
 	if err != nil {
 		return err
@@ -81,12 +113,44 @@ func (p *CanonicalPrinter) Fprint(v interface{}) error {
 		return n.Err()
 	}
 
-	return p.tree(n)
+	p.hints = collectHints(n)
+
+	// Wrapping requires knowledge about the output. We print the tree
+	// repeatedly and break lists until all lines fit or there are no
+	// lists left to break.
+	if p.LineWidth > 0 || p.AlignAssignments {
+		for {
+			if err := p.tree(n, io.Discard); err != nil {
+				return err
+			}
+			if !p.wrap() {
+				break
+			}
+		}
+	}
+
+	minwidth := p.TabWidth
+	twmode := tabwriter.DiscardEmptyColumns | tabwriter.StripEscape
+	if !p.UseSpaces {
+		minwidth = 0
+		twmode |= tabwriter.TabIndent
+	}
+	return p.tree(n, tabwriter.NewWriter(p.w, minwidth, p.TabWidth, 1, ' ', twmode))
 }
 
 // tree prints the syntax tree by interspersing the token stream with spacing
 // information.
-func (p *CanonicalPrinter) tree(n syntax.Node) error {
+func (p *CanonicalPrinter) tree(n syntax.Node, w io.Writer) error {
+	indent := p.Indent
+	defer func() { p.Indent = indent }()
+
+	p.out = w
+	p.lastPos = syntax.Position{}
+	p.whiteBuf = ""
+	p.line, p.col, p.pending = 0, 0, -1
+	p.widths = make(map[int]int)
+	p.tokLine = make(map[int]int)
+	starts := make(map[int]bool)
 
 	// Prime the position tracker with the first token to avoid printing
 	// white-spaces before the first token.
@@ -94,20 +158,53 @@ func (p *CanonicalPrinter) tree(n syntax.Node) error {
 
 	for tok := n.FirstTok(); tok != nil && tok.Kind() != syntax.EOF; tok = tok.NextTok() {
 		p.printToken(tok)
+		if p.lineFirst == tok.Pos() {
+			starts[tok.Pos()] = true
+		}
 	}
+	p.starts = starts
 
 	// Terminate the last line with a newline.
 	if !p.firstToken {
-		fmt.Fprint(p.w, "\n")
+		fmt.Fprint(p.out, "\n")
 	}
 
-	if tw, ok := p.w.(*tabwriter.Writer); ok {
+	if tw, ok := p.out.(*tabwriter.Writer); ok {
 		return tw.Flush()
 	}
 
 	return nil
 }
 
+// wrap breaks the outermost list of every line exceeding the line width. It
+// returns false if there was nothing left to break.
+func (p *CanonicalPrinter) wrap() bool {
+	if p.LineWidth <= 0 {
+		return false
+	}
+	candidates := make(map[int]*list)
+	for _, l := range p.hints.lists {
+		if l.broken {
+			continue
+		}
+		line, ok := p.tokLine[l.open]
+		if !ok || p.tokLine[l.close] != line || p.widths[line] <= p.LineWidth {
+			continue
+		}
+		if c, ok := candidates[line]; !ok || l.open < c.open {
+			candidates[line] = l
+		}
+	}
+	for _, l := range candidates {
+		l.broken = true
+		for _, pos := range l.elems {
+			p.hints.breaks[pos] = true
+		}
+		p.hints.breaks[l.close] = true
+	}
+	return len(candidates) > 0
+}
+
 func (p *CanonicalPrinter) printToken(n syntax.Token) {
 	// Incorporate user-defined line breaks and token separators
 	// into output stream.
@@ -121,7 +218,21 @@ func (p *CanonicalPrinter) printToken(n syntax.Token) {
 	case currPos.Begin.Column > p.lastPos.Column:
 		p.print(blank)
 	}
+
+	// Line breaks required by line wrapping and blank line normalization.
+	if p.hints != nil && !p.firstToken {
+		if p.hints.breaks[n.Pos()] && !strings.HasSuffix(p.whiteBuf, "\n") {
+			p.print(newline)
+		}
+		if p.hints.defs[n.Pos()] && p.BlankLines >= 0 {
+			p.whiteBuf = strings.Repeat("\n", p.BlankLines+1)
+		}
+	}
 	p.lastPos = currPos.End
+	if p.firstToken || strings.HasSuffix(p.whiteBuf, "\n") {
+		p.lineFirst = n.Pos()
+	}
+	p.pending = n.Pos()
 
 	switch k, s := n.Kind(), n.String(); {
 
@@ -139,7 +250,7 @@ func (p *CanonicalPrinter) printToken(n syntax.Token) {
 
 	// Align assignments.
 	case k == syntax.ASSIGN:
-		p.print(blank, ":=", blank)
+		p.print(blank, p.assignPadding(n), ":=", blank)
 
 	// Every line of a comment has to be indented individually.
 	case k == syntax.COMMENT:
@@ -223,7 +334,13 @@ func (p *CanonicalPrinter) print(args ...interface{}) {
 				p.printSpace()
 				p.whiteBuf = ""
 			}
-			fmt.Fprint(p.w, arg)
+			if p.pending >= 0 {
+				p.tokLine[p.pending] = p.line
+				p.pending = -1
+			}
+			s := fmt.Sprint(arg)
+			p.track(s)
+			fmt.Fprint(p.out, s)
 		}
 
 	}
@@ -235,12 +352,55 @@ func (p *CanonicalPrinter) printSpace() {
 		p.firstToken = false
 		return
 	}
-	fmt.Fprint(p.w, p.whiteBuf)
+	fmt.Fprint(p.out, p.whiteBuf)
+	p.track(p.whiteBuf)
 	if strings.HasSuffix(p.whiteBuf, "\n") {
 		for i := 0; i < p.Indent; i++ {
-			fmt.Fprint(p.w, "\t")
+			fmt.Fprint(p.out, "\t")
+			p.col += p.TabWidth
+		}
+	}
+}
+
+// track updates the current output line and column. Tabs between cells are
+// counted as single blank, escape characters are not counted at all.
+func (p *CanonicalPrinter) track(s string) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\n':
+			p.line++
+			p.col = 0
+		case c == '\xff', c&0xc0 == 0x80:
+			// Escape characters and UTF-8 continuation bytes
+		default:
+			p.col++
+		}
+	}
+	if p.col > p.widths[p.line] {
+		p.widths[p.line] = p.col
+	}
+}
+
+// assignPadding returns the blanks required to align the given assignment
+// token with the other field assignments of its composite literal.
+func (p *CanonicalPrinter) assignPadding(tok syntax.Token) string {
+	if !p.AlignAssignments || p.hints == nil {
+		return ""
+	}
+	a, ok := p.hints.assigns[tok.Pos()]
+	if !ok || p.lineFirst != a.first {
+		return ""
+	}
+	max := 0
+	for _, b := range a.group {
+		if p.starts[b.first] && b.width > max {
+			max = b.width
 		}
 	}
+	if max <= a.width {
+		return ""
+	}
+	return strings.Repeat(" ", max-a.width)
 }
 
 func toBytes(v interface{}) ([]byte, error) {
@@ -96,3 +96,46 @@ func TestSimpleFormatter(t *testing.T) {
 		})
 	}
 }
+
+func TestPrettyPrinter(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		// Lists fitting into the line are not wrapped.
+		{input: "f(a, b);", width: 20, want: "f(a, b);\n"},
+
+		// Wrap argument lists.
+		{input: "f(aaaa, bbbb, cccc);", width: 10, want: "f(\n\taaaa,\n\tbbbb,\n\tcccc\n);\n"},
+
+		// Wrap outermost list first.
+		{input: "f(aaaaaa, g(b, c));", width: 16, want: "f(\n\taaaaaa,\n\tg(b, c)\n);\n"},
+
+		// Wrap parameter lists.
+		{input: "function f(integer a, integer b) {}", width: 20, want: "function f(\n\tinteger a,\n\tinteger b\n) {}\n"},
+
+		// Wrap composite literals and align field assignments.
+		{input: "template R t := {a := 1, bcd := 2}", width: 20, want: "template R t := {\n\ta   := 1,\n\tbcd := 2\n}\n"},
+
+		// Only align fields starting on their own line.
+		{input: "x := {a := 1,\nbcd := 2, e := 3}", width: 80, want: "x := {a := 1,\n\tbcd := 2, e := 3}\n"},
+		{input: "x := {\na := 1,\nbcd := 2}", width: 80, want: "x := {\n\ta   := 1,\n\tbcd := 2}\n"},
+
+		// Exactly one blank line between module definitions.
+		{input: "module M {\nconst integer a := 1;\n\n\n\nconst integer b := 2; const integer c := 3;\n}", width: 80, want: "module M {\n\tconst integer a := 1;\n\n\tconst integer b := 2;\n\n\tconst integer c := 3;\n}\n"},
+
+		// Leading comments belong to the definition.
+		{input: "module M {\nconst integer a := 1; // a\n// b\nconst integer b := 2;\n}", width: 80, want: "module M {\n\tconst integer a := 1; // a\n\n\t// b\n\tconst integer b := 2;\n}\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		p := NewPrettyPrinter(&buf, tt.width)
+		if err := p.Fprint(tt.input); err != nil {
+			t.Errorf("%q: %s", tt.input, err.Error())
+			continue
+		}
+		assert.Equal(t, tt.want, buf.String(), "input: %q", tt.input)
+	}
+}
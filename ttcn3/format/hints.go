@@ -0,0 +1,144 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/nokia/ntt/ttcn3/syntax"
+)
+
+// hints provide syntactic context to the token based CanonicalPrinter. All
+// maps are keyed by token offsets.
+type hints struct {
+	// breaks are tokens which must start a new line.
+	breaks map[int]bool
+
+	// defs are the first tokens of module definitions (including their
+	// leading comments), except of the first definition of a module or
+	// group.
+	defs map[int]bool
+
+	// lists are the lists which might be wrapped.
+	lists []*list
+
+	// assigns maps ":=" tokens of field assignments to alignment
+	// information.
+	assigns map[int]*assign
+}
+
+// A list is a parameter list, an argument list or a composite literal.
+type list struct {
+	open, close int
+	elems       []int
+	broken      bool
+}
+
+// An assign describes a field assignment of a composite literal.
+type assign struct {
+	first int // first token of the field assignment
+	width int // width of the left hand side
+	group []*assign
+}
+
+func collectHints(n syntax.Node) *hints {
+	h := &hints{
+		breaks:  make(map[int]bool),
+		defs:    make(map[int]bool),
+		assigns: make(map[int]*assign),
+	}
+
+	addDefs := func(defs []*syntax.ModuleDef) {
+		for i, d := range defs {
+			if i > 0 {
+				h.defs[leadingTok(d).Pos()] = true
+			}
+		}
+	}
+
+	addList := func(open, close syntax.Token, elems []syntax.Node) {
+		if syntax.IsNil(open) || syntax.IsNil(close) || len(elems) == 0 {
+			return
+		}
+		l := &list{open: open.Pos(), close: close.Pos()}
+		for _, e := range elems {
+			if tok := e.FirstTok(); tok != nil {
+				l.elems = append(l.elems, tok.Pos())
+			}
+		}
+		h.lists = append(h.lists, l)
+	}
+
+	n.Inspect(func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.Module:
+			addDefs(n.Defs)
+		case *syntax.GroupDecl:
+			addDefs(n.Defs)
+		case *syntax.FormalPars:
+			elems := make([]syntax.Node, len(n.List))
+			for i, e := range n.List {
+				elems[i] = e
+			}
+			addList(n.LParen, n.RParen, elems)
+		case *syntax.ParenExpr:
+			if !syntax.IsNil(n.LParen) && n.LParen.Kind() == syntax.LPAREN {
+				addList(n.LParen, n.RParen, exprs(n.List))
+			}
+		case *syntax.CompositeLiteral:
+			addList(n.LBrace, n.RBrace, exprs(n.List))
+			var group []*assign
+			for _, e := range n.List {
+				if b, ok := e.(*syntax.BinaryExpr); ok && !syntax.IsNil(b.Op) && b.Op.Kind() == syntax.ASSIGN && !syntax.IsNil(b.X) {
+					a := &assign{first: b.FirstTok().Pos(), width: len(text(b.X))}
+					h.assigns[b.Op.Pos()] = a
+					group = append(group, a)
+				}
+			}
+			for _, a := range group {
+				a.group = group
+			}
+		}
+		return true
+	})
+	return h
+}
+
+// leadingTok returns the first token of a node including the comments
+// preceding it. Trailing comments of the previous line are not included.
+func leadingTok(n syntax.Node) syntax.Token {
+	tok := n.FirstTok()
+	for prev := tok.PrevTok(); prev != nil && prev.Kind() == syntax.COMMENT; prev = prev.PrevTok() {
+		if pp := prev.PrevTok(); pp != nil && syntax.Begin(prev).Line <= syntax.End(pp).Line {
+			break
+		}
+		tok = prev
+	}
+	return tok
+}
+
+// text returns the tokens of n separated by single blanks where the source has
+// white-space.
+func text(n syntax.Node) string {
+	var (
+		sb   strings.Builder
+		last syntax.Token
+	)
+	for tok := n.FirstTok(); tok != nil; tok = tok.NextTok() {
+		if last != nil && tok.Pos() > last.End() {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(tok.String())
+		if tok.Pos() == n.LastTok().Pos() {
+			break
+		}
+		last = tok
+	}
+	return sb.String()
+}
+
+func exprs(list []syntax.Expr) []syntax.Node {
+	nodes := make([]syntax.Node, len(list))
+	for i, e := range list {
+		nodes[i] = e
+	}
+	return nodes
+}
@@ -0,0 +1,206 @@
+// Package graph provides the module import graph of TTCN-3 source files.
+package graph
+
+import (
+	"sort"
+
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/syntax"
+)
+
+// Graph is a directed graph of TTCN-3 modules. An edge from module A to module
+// B means module A imports module B.
+type Graph struct {
+	Modules map[string]*Module
+}
+
+// Module is a node of the import graph.
+type Module struct {
+	Name string `json:"name"`
+
+	// File is the file defining the module. File is empty if the module is
+	// imported, but could not be found.
+	File string `json:"file,omitempty"`
+
+	// Imports is the sorted list of modules imported.
+	Imports []string `json:"imports,omitempty"`
+}
+
+// New returns the import graph of the modules defined in files.
+func New(files ...string) *Graph {
+	g := &Graph{Modules: make(map[string]*Module)}
+	for _, file := range files {
+		tree := ttcn3.ParseFile(file)
+		if tree.Root == nil {
+			continue
+		}
+		for _, n := range tree.Modules() {
+			m := g.add(syntax.Name(n.Node))
+			m.File = file
+			deps := make(map[string]bool)
+			n.Node.Inspect(func(n syntax.Node) bool {
+				if x, ok := n.(*syntax.ImportDecl); ok {
+					deps[syntax.Name(x.Module)] = true
+					return false
+				}
+				return true
+			})
+			for dep := range deps {
+				g.add(dep)
+				m.Imports = append(m.Imports, dep)
+			}
+			sort.Strings(m.Imports)
+		}
+	}
+	return g
+}
+
+func (g *Graph) add(name string) *Module {
+	if m, ok := g.Modules[name]; ok {
+		return m
+	}
+	m := &Module{Name: name}
+	g.Modules[name] = m
+	return m
+}
+
+// Names returns the sorted names of all modules.
+func (g *Graph) Names() []string {
+	names := make([]string, 0, len(g.Modules))
+	for name := range g.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Deps returns the sorted names of the given modules and all modules they
+// import, directly or indirectly.
+func (g *Graph) Deps(roots ...string) []string {
+	seen := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		if m, ok := g.Modules[name]; ok {
+			for _, dep := range m.Imports {
+				visit(dep)
+			}
+		}
+	}
+	for _, root := range roots {
+		visit(root)
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Subgraph returns the graph induced by the given module names.
+func (g *Graph) Subgraph(names ...string) *Graph {
+	keep := make(map[string]bool)
+	for _, name := range names {
+		keep[name] = true
+	}
+	sub := &Graph{Modules: make(map[string]*Module)}
+	for name, m := range g.Modules {
+		if !keep[name] {
+			continue
+		}
+		c := &Module{Name: m.Name, File: m.File}
+		for _, dep := range m.Imports {
+			if keep[dep] {
+				c.Imports = append(c.Imports, dep)
+			}
+		}
+		sub.Modules[name] = c
+	}
+	return sub
+}
+
+// SCC returns the strongly connected components of the graph. Modules of each
+// component are sorted and components are sorted by their first module.
+func (g *Graph) SCC() [][]string {
+	// Tarjan's algorithm
+	var (
+		index   = make(map[string]int)
+		lowlink = make(map[string]int)
+		onStack = make(map[string]bool)
+		stack   []string
+		result  [][]string
+		connect func(v string)
+	)
+
+	connect = func(v string) {
+		index[v] = len(index)
+		lowlink[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range g.Modules[v].Imports {
+			if _, ok := index[w]; !ok {
+				connect(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], index[w])
+			}
+		}
+
+		if lowlink[v] == index[v] {
+			var scc []string
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				scc = append(scc, w)
+				if w == v {
+					break
+				}
+			}
+			sort.Strings(scc)
+			result = append(result, scc)
+		}
+	}
+
+	for _, name := range g.Names() {
+		if _, ok := index[name]; !ok {
+			connect(name)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i][0] < result[j][0] })
+	return result
+}
+
+// Cycles returns the strongly connected components, which form import cycles.
+// This includes modules importing themselves.
+func (g *Graph) Cycles() [][]string {
+	var cycles [][]string
+	for _, scc := range g.SCC() {
+		if len(scc) > 1 || g.imports(scc[0], scc[0]) {
+			cycles = append(cycles, scc)
+		}
+	}
+	return cycles
+}
+
+func (g *Graph) imports(from, to string) bool {
+	for _, dep := range g.Modules[from].Imports {
+		if dep == to {
+			return true
+		}
+	}
+	return false
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,60 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func newGraph(t *testing.T, src string) *graph.Graph {
+	file := "test://" + t.Name() + ".ttcn3"
+	fs.SetContent(file, []byte(src))
+	return graph.New(file)
+}
+
+func TestImports(t *testing.T) {
+	g := newGraph(t, `
+		module A { import from B all; import from C all; import from B { type T } }
+		module B { group G { import from C all } }
+		module C {}`)
+
+	assert.Equal(t, []string{"A", "B", "C"}, g.Names())
+	assert.Equal(t, []string{"B", "C"}, g.Modules["A"].Imports)
+	assert.Equal(t, []string{"C"}, g.Modules["B"].Imports)
+	assert.Nil(t, g.Modules["C"].Imports)
+}
+
+func TestMissingModules(t *testing.T) {
+	g := newGraph(t, `module A { import from X all }`)
+	assert.Equal(t, []string{"A", "X"}, g.Names())
+	assert.Equal(t, "", g.Modules["X"].File)
+}
+
+func TestCycles(t *testing.T) {
+	g := newGraph(t, `
+		module A { import from B all }
+		module B { import from C all }
+		module C { import from A all; import from D all }
+		module D { import from E all }
+		module E { import from E all }`)
+
+	assert.Equal(t, [][]string{{"A", "B", "C"}, {"D"}, {"E"}}, g.SCC())
+	assert.Equal(t, [][]string{{"A", "B", "C"}, {"E"}}, g.Cycles())
+}
+
+func TestDeps(t *testing.T) {
+	g := newGraph(t, `
+		module A { import from B all }
+		module B { import from C all }
+		module C {}
+		module D { import from A all }`)
+
+	assert.Equal(t, []string{"A", "B", "C"}, g.Deps("A"))
+	assert.Equal(t, []string{"C"}, g.Deps("C"))
+
+	sub := g.Subgraph(g.Deps("B")...)
+	assert.Equal(t, []string{"B", "C"}, sub.Names())
+	assert.Equal(t, []string{"C"}, sub.Modules["B"].Imports)
+}
@@ -0,0 +1,264 @@
+// Package impact computes the testcases affected by changes of TTCN-3 source
+// files.
+package impact
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/syntax"
+)
+
+// A Change describes the modified lines of a file. A change without lines
+// modifies the whole file.
+type Change struct {
+	File string
+
+	// Lines are inclusive ranges of modified lines.
+	Lines [][2]int
+}
+
+// Affected returns the sorted qualified names of the testcases, which depend
+// directly or indirectly on definitions modified by the given changes.
+// Dependencies are resolved using the references recorded in db and the
+// imports of the referencing modules.
+//
+// Affected is conservative: references are matched by name, and changed
+// imports or lines outside of any definition modify all definitions of the
+// module.
+func Affected(db *ttcn3.DB, changes ...Change) []string {
+	var (
+		q     []def
+		seen  = make(map[def]bool)
+		tests = make(map[string]bool)
+	)
+
+	add := func(d def) {
+		if !seen[d] {
+			seen[d] = true
+			q = append(q, d)
+		}
+	}
+
+	for _, c := range changes {
+		tree := ttcn3.ParseFile(c.File)
+		for _, m := range tree.Modules() {
+			mod := m.Node.(*syntax.Module)
+			for _, d := range changedDefs(mod, c.Lines) {
+				add(d)
+			}
+		}
+	}
+
+	for len(q) > 0 {
+		d := q[0]
+		q = q[1:]
+		if d.test {
+			tests[d.module+"."+d.name] = true
+		}
+		for file := range db.Uses[d.name] {
+			tree := ttcn3.ParseFile(file)
+			for _, m := range tree.Modules() {
+				mod := m.Node.(*syntax.Module)
+				if name := syntax.Name(mod.Name); name != d.module && !imports(mod, d.module) {
+					continue
+				}
+				for _, md := range moduleDefs(mod.Defs) {
+					if references(md.Def, d.name) {
+						for _, x := range defsOf(mod, md) {
+							add(x)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	ret := make([]string, 0, len(tests))
+	for name := range tests {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// def identifies a module definition.
+type def struct {
+	module string
+	name   string
+	test   bool
+}
+
+// changedDefs returns the definitions of mod overlapping the given lines.
+func changedDefs(mod *syntax.Module, lines [][2]int) []def {
+	defs := moduleDefs(mod.Defs)
+	if len(lines) == 0 {
+		return defsOf(mod, defs...)
+	}
+
+	var ret []def
+	for _, r := range lines {
+		begin, end := syntax.Begin(mod), syntax.End(mod)
+		if r[1] < begin.Line || r[0] > end.Line {
+			continue
+		}
+		found := false
+		for _, md := range defs {
+			if b, e := syntax.Begin(md), syntax.End(md); r[1] >= b.Line && r[0] <= e.Line {
+				// Imports change the meaning of all references.
+				if _, ok := md.Def.(*syntax.ImportDecl); ok {
+					return defsOf(mod, defs...)
+				}
+				ret = append(ret, defsOf(mod, md)...)
+				found = true
+			}
+		}
+		if !found {
+			return defsOf(mod, defs...)
+		}
+	}
+	return ret
+}
+
+// moduleDefs returns the module definitions. Groups are flattened.
+func moduleDefs(defs []*syntax.ModuleDef) []*syntax.ModuleDef {
+	var ret []*syntax.ModuleDef
+	for _, md := range defs {
+		if g, ok := md.Def.(*syntax.GroupDecl); ok {
+			ret = append(ret, moduleDefs(g.Defs)...)
+			continue
+		}
+		ret = append(ret, md)
+	}
+	return ret
+}
+
+// defsOf returns the names declared by the given module definitions.
+func defsOf(mod *syntax.Module, defs ...*syntax.ModuleDef) []def {
+	module := syntax.Name(mod.Name)
+	var ret []def
+	for _, md := range defs {
+		test := false
+		if f, ok := md.Def.(*syntax.FuncDecl); ok {
+			test = f.IsTest()
+		}
+		for _, name := range declaredNames(md.Def) {
+			ret = append(ret, def{module: module, name: name, test: test})
+		}
+	}
+	return ret
+}
+
+func declaredNames(n syntax.Node) []string {
+	switch n := n.(type) {
+	case *syntax.ValueDecl:
+		var names []string
+		for _, d := range n.Decls {
+			names = append(names, syntax.Name(d.Name))
+		}
+		return names
+	case *syntax.ModuleParameterGroup:
+		var names []string
+		for _, d := range n.Decls {
+			names = append(names, declaredNames(d)...)
+		}
+		return names
+	}
+	if name := syntax.Name(n); name != "" {
+		return []string{name}
+	}
+	return nil
+}
+
+// imports returns true if mod imports the given module.
+func imports(mod *syntax.Module, name string) bool {
+	found := false
+	mod.Inspect(func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.ImportDecl:
+			if syntax.Name(n.Module) == name {
+				found = true
+			}
+			return false
+		case *syntax.Module, *syntax.ModuleDef, *syntax.GroupDecl:
+			return !found
+		}
+		return false
+	})
+	return found
+}
+
+// references returns true if n references name.
+func references(n syntax.Node, name string) bool {
+	found := false
+	n.Inspect(func(n syntax.Node) bool {
+		if found {
+			return false
+		}
+		if id, ok := n.(*syntax.Ident); ok {
+			if !id.IsName && id.String() == name {
+				found = true
+			}
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// ParseDiff returns the changes described by a unified diff, as produced by
+// git diff. Only the new side of each hunk is considered. File names are
+// returned as they appear in the diff, without prefixes a/ and b/. Deleted
+// files are ignored.
+func ParseDiff(b []byte) []Change {
+	var (
+		ret []Change
+		cur = -1
+	)
+	for _, line := range strings.Split(string(b), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			cur = -1
+			name := strings.TrimSpace(strings.TrimPrefix(line, "+++ "))
+			if i := strings.IndexByte(name, '\t'); i >= 0 {
+				name = name[:i]
+			}
+			if name == "/dev/null" {
+				continue
+			}
+			ret = append(ret, Change{File: strings.TrimPrefix(name, "b/"), Lines: [][2]int{}})
+			cur = len(ret) - 1
+
+		case strings.HasPrefix(line, "@@ ") && cur >= 0:
+			if r, ok := parseHunk(line); ok {
+				ret[cur].Lines = append(ret[cur].Lines, r)
+			}
+		}
+	}
+	return ret
+}
+
+// parseHunk returns the line range of the new side of a hunk header of the
+// form "@@ -l,s +l,s @@". Pure deletions are reported as the line
+// preceding and following the deletion.
+func parseHunk(s string) ([2]int, bool) {
+	f := strings.Fields(s)
+	if len(f) < 3 || !strings.HasPrefix(f[2], "+") {
+		return [2]int{}, false
+	}
+	start, count := f[2][1:], "1"
+	if i := strings.IndexByte(start, ','); i >= 0 {
+		start, count = start[:i], start[i+1:]
+	}
+	l, err1 := strconv.Atoi(start)
+	n, err2 := strconv.Atoi(count)
+	if err1 != nil || err2 != nil {
+		return [2]int{}, false
+	}
+	if n == 0 {
+		return [2]int{l, l + 1}, true
+	}
+	return [2]int{l, l + n - 1}, true
+}
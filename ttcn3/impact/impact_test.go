@@ -0,0 +1,106 @@
+package impact_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/impact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAffected(t *testing.T) {
+	files := map[string]string{
+		"test://A.ttcn3": `module A {
+const integer x := 1;
+function f() { log(x) }
+group g {
+  const integer y := 2;
+}
+type component C {}
+}`,
+		"test://B.ttcn3": `module B {
+import from A all;
+testcase tc1() runs on C { f() }
+testcase tc2() runs on C { log(y) }
+
+// Helpers
+testcase tc3() runs on C { tc1_helper() }
+function tc1_helper() {}
+}`,
+		"test://D.ttcn3": `module D {
+testcase tc4() { f() }
+}`,
+	}
+
+	var names []string
+	for name, src := range files {
+		fs.SetContent(name, []byte(src))
+		names = append(names, name)
+	}
+	db := &ttcn3.DB{}
+	db.Index(names...)
+
+	tests := []struct {
+		Change impact.Change
+		Want   []string
+	}{
+		// Constant x is used by function f, which is used by tc1.
+		{Change: impact.Change{File: "test://A.ttcn3", Lines: [][2]int{{2, 2}}}, Want: []string{"B.tc1"}},
+
+		// Definitions in groups are supported.
+		{Change: impact.Change{File: "test://A.ttcn3", Lines: [][2]int{{5, 5}}}, Want: []string{"B.tc2"}},
+
+		// Component C is used by all tests of B.
+		{Change: impact.Change{File: "test://A.ttcn3", Lines: [][2]int{{7, 7}}}, Want: []string{"B.tc1", "B.tc2", "B.tc3"}},
+
+		// Changes of imports affect the whole module.
+		{Change: impact.Change{File: "test://B.ttcn3", Lines: [][2]int{{2, 2}}}, Want: []string{"B.tc1", "B.tc2", "B.tc3"}},
+
+		// Changes outside of definitions affect the whole module.
+		{Change: impact.Change{File: "test://B.ttcn3", Lines: [][2]int{{5, 5}}}, Want: []string{"B.tc1", "B.tc2", "B.tc3"}},
+		{Change: impact.Change{File: "test://B.ttcn3", Lines: [][2]int{{6, 6}}}, Want: []string{"B.tc1", "B.tc2", "B.tc3"}},
+
+		// Changed testcases are affected themselves.
+		{Change: impact.Change{File: "test://B.ttcn3", Lines: [][2]int{{4, 4}}}, Want: []string{"B.tc2"}},
+		{Change: impact.Change{File: "test://B.ttcn3", Lines: [][2]int{{7, 7}}}, Want: []string{"B.tc3"}},
+		{Change: impact.Change{File: "test://B.ttcn3", Lines: [][2]int{{8, 8}}}, Want: []string{"B.tc3"}},
+
+		// Changes of whole files.
+		{Change: impact.Change{File: "test://D.ttcn3"}, Want: []string{"D.tc4"}},
+
+		// Lines outside of any module are ignored.
+		{Change: impact.Change{File: "test://A.ttcn3", Lines: [][2]int{{100, 120}}}, Want: []string{}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.Want, impact.Affected(db, tt.Change), "%v", tt.Change)
+	}
+}
+
+func TestParseDiff(t *testing.T) {
+	input := `diff --git a/src/A.ttcn3 b/src/A.ttcn3
+index 1111111..2222222 100644
+--- a/src/A.ttcn3
++++ b/src/A.ttcn3
+@@ -2 +2 @@ module A {
+-const integer x := 1;
++const integer x := 2;
+@@ -10,3 +10,0 @@ function f() {
+@@ -20,0 +18,4 @@
+diff --git a/src/B.ttcn3 b/src/B.ttcn3
+deleted file mode 100644
+--- a/src/B.ttcn3
++++ /dev/null
+@@ -1,3 +0,0 @@
+diff --git a/src/C.ttcn3 b/src/C.ttcn3
+new file mode 100644
+--- /dev/null
++++ b/src/C.ttcn3
+@@ -0,0 +1,5 @@
+`
+	assert.Equal(t, []impact.Change{
+		{File: "src/A.ttcn3", Lines: [][2]int{{2, 2}, {10, 11}, {18, 21}}},
+		{File: "src/C.ttcn3", Lines: [][2]int{{1, 5}}},
+	}, impact.ParseDiff([]byte(input)))
+}
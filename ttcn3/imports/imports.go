@@ -0,0 +1,415 @@
+// Package imports organizes TTCN-3 import statements.
+//
+// Organizing imports merges duplicate imports, sorts imports by module name,
+// converts "import from X all" into explicit import lists of the symbols
+// actually used and removes imports which provide nothing used.
+//
+// Imports using features like except-clauses, group- or import-imports,
+// language specifications, with-attributes or visibility modifiers are kept
+// as they are, because their effect cannot be reproduced by an explicit list.
+// So are imports of modules not found in the database. Symbols of other
+// imports from the same module are still imported explicitly, unless a kept
+// import provides them already.
+package imports
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/syntax"
+)
+
+// An Edit replaces the source text between the offsets Pos and End with
+// Text.
+type Edit struct {
+	Pos, End int
+	Text     string
+}
+
+// Apply applies the edits to src and returns the result. Edits must not
+// overlap.
+func Apply(src []byte, edits []Edit) []byte {
+	edits = append([]Edit(nil), edits...)
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Pos < edits[j].Pos })
+
+	var (
+		sb   strings.Builder
+		last int
+	)
+	for _, e := range edits {
+		sb.Write(src[last:e.Pos])
+		sb.WriteString(e.Text)
+		last = e.End
+	}
+	sb.Write(src[last:])
+	return []byte(sb.String())
+}
+
+// Kinds lists the definition kinds in the order they are printed in import
+// lists.
+var Kinds = []string{"type", "template", "const", "modulepar", "signature", "function", "altstep", "testcase"}
+
+// Symbols maps definition kinds (type, const, ...) to sets of names.
+type Symbols map[string]map[string]bool
+
+func (s Symbols) add(kind, name string) {
+	if s[kind] == nil {
+		s[kind] = make(map[string]bool)
+	}
+	s[kind][name] = true
+}
+
+// Used returns the symbols a module uses from other modules, grouped by
+// module name. Identifiers are resolved using db.
+func Used(tree *ttcn3.Tree, mod *syntax.Module, db *ttcn3.DB) map[string]Symbols {
+	used := make(map[string]Symbols)
+	self := syntax.Name(mod.Name)
+
+	mod.Inspect(func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.ImportDecl:
+			return false
+		case *syntax.SelectorExpr:
+			// Selectors (fields, ...) are not resolved by their
+			// own, but their base expression might be.
+			if n.X != nil {
+				n.X.Inspect(func(n syntax.Node) bool {
+					return visit(tree, n, self, db, used)
+				})
+			}
+			return false
+		}
+		return visit(tree, n, self, db, used)
+	})
+	return used
+}
+
+func visit(tree *ttcn3.Tree, n syntax.Node, self string, db *ttcn3.DB, used map[string]Symbols) bool {
+	id, ok := n.(*syntax.Ident)
+	if !ok {
+		return true
+	}
+	if id.IsName || id.Tok2 != nil {
+		return false
+	}
+	for _, def := range tree.LookupWithDB(id, db) {
+		mod, kind, name := definition(def)
+		if mod == "" || mod == self {
+			continue
+		}
+		if used[mod] == nil {
+			used[mod] = make(Symbols)
+		}
+		used[mod].add(kind, name)
+	}
+	return false
+}
+
+// definition returns module, kind and name of the module definition declaring
+// def. If def is not declared by a module definition, empty strings are
+// returned.
+func definition(def *ttcn3.Node) (string, string, string) {
+	if def.Tree == nil || def.Ident == nil {
+		return "", "", ""
+	}
+	var md *syntax.ModuleDef
+	for n := def.Tree.ParentOf(def.Node); n != nil; n = def.Tree.ParentOf(n) {
+		if n, ok := n.(*syntax.ModuleDef); ok {
+			md = n
+			break
+		}
+	}
+	if md == nil {
+		return "", "", ""
+	}
+
+	// Only module definitions themselves are interesting, not their
+	// fields or parameters.
+	if sub, ok := md.Def.(*syntax.SubTypeDecl); !(md.Def == def.Node || ok && sub.Field == def.Node) {
+		return "", "", ""
+	}
+
+	kind := Kind(md.Def)
+	if kind == "" {
+		return "", "", ""
+	}
+	mod := def.Tree.ModuleOf(md)
+	if mod == nil {
+		return "", "", ""
+	}
+
+	// Identifiers declared inside a definition, like enumeration labels,
+	// are imported by the name of the enclosing definition. Only value
+	// declarations declare several importable names.
+	name := def.Ident.String()
+	if _, ok := md.Def.(*syntax.ValueDecl); !ok {
+		if n := syntax.Name(md.Def); n != "" {
+			name = n
+		}
+	}
+	return syntax.Name(mod.Name), kind, name
+}
+
+// Kind returns the import kind of a definition, e.g. "type" or "const". Kind
+// returns an empty string if the definition cannot be imported individually.
+func Kind(n syntax.Node) string {
+	switch n := n.(type) {
+	case *syntax.SubTypeDecl, *syntax.StructTypeDecl, *syntax.EnumTypeDecl,
+		*syntax.PortTypeDecl, *syntax.ComponentTypeDecl, *syntax.BehaviourTypeDecl,
+		*syntax.MapTypeDecl, *syntax.ClassTypeDecl:
+		return "type"
+	case *syntax.TemplateDecl:
+		return "template"
+	case *syntax.SignatureDecl:
+		return "signature"
+	case *syntax.ValueDecl:
+		if n.KindTok != nil {
+			switch n.KindTok.Kind() {
+			case syntax.CONST:
+				return "const"
+			case syntax.MODULEPAR:
+				return "modulepar"
+			}
+		}
+	case *syntax.FuncDecl:
+		if n.KindTok != nil {
+			switch n.KindTok.Kind() {
+			case syntax.FUNCTION:
+				return "function"
+			case syntax.ALTSTEP:
+				return "altstep"
+			case syntax.TESTCASE:
+				return "testcase"
+			}
+		}
+	}
+	return ""
+}
+
+// Organize returns the edits required to organize the imports of all
+// modules in tree. Organize returns no edits if the imports are already
+// organized.
+func Organize(tree *ttcn3.Tree, db *ttcn3.DB) ([]Edit, error) {
+	if tree.Err != nil {
+		return nil, tree.Err
+	}
+	src, err := fs.Content(tree.Filename())
+	if err != nil {
+		return nil, err
+	}
+
+	var edits []Edit
+	for _, m := range tree.Modules() {
+		edits = append(edits, organize(tree, m.Node.(*syntax.Module), db, src)...)
+	}
+	if string(Apply(src, edits)) == string(src) {
+		return nil, nil
+	}
+	return edits, nil
+}
+
+func organize(tree *ttcn3.Tree, mod *syntax.Module, db *ttcn3.DB, src []byte) []Edit {
+	var defs []*syntax.ModuleDef
+	mod.Inspect(func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.Module, *syntax.GroupDecl:
+			return true
+		case *syntax.ModuleDef:
+			if _, ok := n.Def.(*syntax.ImportDecl); ok {
+				defs = append(defs, n)
+				return false
+			}
+			return true
+		}
+		return false
+	})
+	if len(defs) == 0 {
+		return nil
+	}
+
+	var (
+		used    = Used(tree, mod, db)
+		names   []string
+		simple  = make(map[string]bool)
+		complex = make(map[string][]string)
+		covered = make(map[string]Symbols)
+	)
+	for _, d := range defs {
+		name := syntax.Name(d.Def.(*syntax.ImportDecl).Module)
+		if _, ok := simple[name]; !ok {
+			if _, ok := complex[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		// Imports of unknown modules are kept, because the symbols
+		// they provide cannot be resolved.
+		if _, ok := db.Modules[name]; ok && isSimple(d) {
+			simple[name] = true
+			continue
+		}
+		text := string(src[d.Pos():end(d)])
+		if !contains(complex[name], text) {
+			complex[name] = append(complex[name], text)
+		}
+		if covered[name] == nil {
+			covered[name] = make(Symbols)
+		}
+		cover(covered[name], d.Def.(*syntax.ImportDecl))
+	}
+	sort.Strings(names)
+
+	first := expand(src, defs[0].Pos(), end(defs[0]))
+	var lines []string
+	for _, name := range names {
+		for _, text := range complex[name] {
+			lines = append(lines, strings.TrimSuffix(text, ";")+";")
+		}
+		if !simple[name] {
+			continue
+		}
+		if syms := subtract(used[name], covered[name]); len(syms) > 0 {
+			lines = append(lines, Format(name, syms, first.indent))
+		}
+	}
+
+	var edits []Edit
+	for i, d := range defs {
+		r := expand(src, d.Pos(), end(d))
+		e := Edit{Pos: r.pos, End: r.end}
+		if i == 0 && len(lines) > 0 {
+			e.Text = r.indent + strings.Join(lines, "\n"+r.indent)
+			if r.wholeLine {
+				e.Text += "\n"
+			}
+		}
+		edits = append(edits, e)
+	}
+	return edits
+}
+
+// Format formats an explicit import statement.
+func Format(mod string, syms Symbols, indent string) string {
+	var sb strings.Builder
+	sb.WriteString("import from " + mod + " {\n")
+	for _, kind := range Kinds {
+		if len(syms[kind]) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(syms[kind]))
+		for name := range syms[kind] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		sb.WriteString(indent + "\t" + kind + " " + strings.Join(names, ", ") + ";\n")
+	}
+	sb.WriteString(indent + "}")
+	return sb.String()
+}
+
+// cover adds the symbols imported explicitly by x to syms. Imports of all
+// definitions of a kind are added with the name "all", imports of all
+// definitions with the empty kind. Except-clauses are ignored, which is safe,
+// because symbols not covered are imported again.
+func cover(syms Symbols, x *syntax.ImportDecl) {
+	for _, k := range x.List {
+		kind := ""
+		if k.KindTok != nil {
+			kind = k.KindTok.String()
+		}
+		for _, e := range k.List {
+			if id, ok := e.(*syntax.Ident); ok {
+				syms.add(kind, id.String())
+			}
+		}
+	}
+}
+
+// subtract returns the symbols of a, which are not covered by b.
+func subtract(a, b Symbols) Symbols {
+	ret := make(Symbols)
+	for kind, names := range a {
+		for name := range names {
+			if !b[kind][name] && !b[kind]["all"] && !b[""]["all"] {
+				ret.add(kind, name)
+			}
+		}
+	}
+	return ret
+}
+
+// isSimple returns true if the import definition can be replaced by an
+// explicit import list.
+func isSimple(d *syntax.ModuleDef) bool {
+	x := d.Def.(*syntax.ImportDecl)
+	if d.Visibility != nil || x.Language != nil || x.With != nil {
+		return false
+	}
+	for _, k := range x.List {
+		if k.KindTok != nil {
+			switch k.KindTok.Kind() {
+			case syntax.ALTSTEP, syntax.CONST, syntax.FUNCTION, syntax.MODULEPAR,
+				syntax.SIGNATURE, syntax.TEMPLATE, syntax.TESTCASE, syntax.TYPE:
+			default:
+				return false
+			}
+		}
+		for _, e := range k.List {
+			if _, ok := e.(*syntax.Ident); !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// end returns the end of a module definition including a terminating
+// semicolon.
+func end(d *syntax.ModuleDef) int {
+	tok := d.LastTok()
+	if next := tok.NextTok(); next != nil && next.Kind() == syntax.SEMICOLON {
+		return next.End()
+	}
+	return tok.End()
+}
+
+type region struct {
+	pos, end  int
+	indent    string
+	wholeLine bool
+}
+
+// expand expands the region [pos, end) to whole lines, if the region is only
+// surrounded by white-space.
+func expand(src []byte, pos, end int) region {
+	r := region{pos: pos, end: end}
+	i := pos
+	for i > 0 && (src[i-1] == ' ' || src[i-1] == '\t') {
+		i--
+	}
+	lineStart := i == 0 || src[i-1] == '\n'
+	if lineStart {
+		r.indent = string(src[i:pos])
+		r.pos = i
+	}
+	j := end
+	for j < len(src) && (src[j] == ' ' || src[j] == '\t' || src[j] == '\r') {
+		j++
+	}
+	r.end = j
+	if lineStart && j < len(src) && src[j] == '\n' {
+		r.end = j + 1
+		r.wholeLine = true
+	}
+	return r
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package imports_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/imports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganize(t *testing.T) {
+	lib := `
+module A {
+	type integer T1;
+	type record R { integer x }
+	const integer c1 := 1, c2 := 2;
+	template R t := { x := 1 }
+	function f() {}
+}
+module B {
+	const integer unused := 0;
+}
+module C {
+	type integer T3;
+}
+module E {
+	type enumerated Color { red, green }
+}`
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name: "all to explicit",
+			input: `module M {
+	import from A all;
+	function g(T1 p) { var R r := t; r.x := c2; f(); }
+}`,
+			want: `module M {
+	import from A {
+		type R, T1;
+		template t;
+		const c2;
+		function f;
+	}
+	function g(T1 p) { var R r := t; r.x := c2; f(); }
+}`,
+		},
+		{
+			name: "remove unused and duplicates, sort",
+			input: `module M {
+	import from C all;
+	import from B all;
+	import from A { type T1 };
+	import from C { type T3 };
+	const T3 x := 1;
+	const T1 y := 2;
+}`,
+			want: `module M {
+	import from A {
+		type T1;
+	}
+	import from C {
+		type T3;
+	}
+	const T3 x := 1;
+	const T1 y := 2;
+}`,
+		},
+		{
+			name: "keep complex imports",
+			input: `module M {
+	import from C all except { type T3 };
+	import from A all;
+	const T1 y := 2;
+}`,
+			want: `module M {
+	import from A {
+		type T1;
+	}
+	import from C all except { type T3 };
+	const T1 y := 2;
+}`,
+		},
+		{
+			name: "keep simple imports next to complex imports",
+			input: `module M {
+	import from A { type T1 };
+	import from A { const c1 } with { extension "x" };
+	const T1 x := c1;
+}`,
+			want: `module M {
+	import from A { const c1 } with { extension "x" };
+	import from A {
+		type T1;
+	}
+	const T1 x := c1;
+}`,
+		},
+		{
+			name: "enum labels import their type",
+			input: `module M {
+	import from E all;
+	function g() { log(red) }
+}`,
+			want: `module M {
+	import from E {
+		type Color;
+	}
+	function g() { log(red) }
+}`,
+		},
+		{
+			name: "keep imports of unknown modules",
+			input: `module M {
+	import from Missing all;
+	import from A all;
+	const T1 y := 2;
+}`,
+			want: `module M {
+	import from A {
+		type T1;
+	}
+	import from Missing all;
+	const T1 y := 2;
+}`,
+		},
+		{
+			name: "already organized",
+			input: `module M {
+	import from A {
+		type T1;
+	}
+	const T1 y := 2;
+}`,
+			want: `module M {
+	import from A {
+		type T1;
+	}
+	const T1 y := 2;
+}`,
+		},
+	}
+
+	fs.SetContent("test://lib.ttcn3", []byte(lib))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := "test://" + tt.name + ".ttcn3"
+			fs.SetContent(file, []byte(tt.input))
+			db := &ttcn3.DB{}
+			db.Index("test://lib.ttcn3", file)
+
+			edits, err := imports.Organize(ttcn3.ParseFile(file), db)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, string(imports.Apply([]byte(tt.input), edits)))
+		})
+	}
+}
@@ -0,0 +1,353 @@
+// Package metrics computes size and complexity metrics of TTCN-3 source code.
+package metrics
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/imports"
+	"github.com/nokia/ntt/ttcn3/syntax"
+)
+
+// Metrics are the metrics of a module or definition.
+type Metrics struct {
+	// LOC is the number of source lines, not counting blank lines and
+	// lines with comments only.
+	LOC int `json:"loc"`
+
+	// Complexity is the cyclomatic complexity (McCabe) of behaviours. The
+	// complexity of modules is the sum of the complexity of their
+	// behaviours.
+	Complexity int `json:"complexity"`
+
+	// Nesting is the maximum nesting depth of statement blocks.
+	Nesting int `json:"nesting"`
+
+	// FanIn is the number of definitions (modules) which reference this
+	// definition (module).
+	FanIn int `json:"fan_in"`
+
+	// FanOut is the number of definitions (modules) referenced by this
+	// definition (module).
+	FanOut int `json:"fan_out"`
+
+	// AltBranches is the number of alternatives of alt- and interleave
+	// statements and of altsteps.
+	AltBranches int `json:"alt_branches"`
+
+	// Templates is the number of templates referenced by a definition or
+	// the number of templates defined by a module.
+	Templates int `json:"templates"`
+}
+
+// Module describes the metrics of a module.
+type Module struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Metrics
+	Definitions []*Definition `json:"definitions,omitempty"`
+}
+
+// Definition describes the metrics of a module definition.
+type Definition struct {
+	Name string `json:"name"` // Qualified name
+	Kind string `json:"kind"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Metrics
+}
+
+// Options control the computation of metrics.
+type Options struct {
+	// IgnoreGuards excludes the guards of alt- and interleave statements
+	// from the cyclomatic complexity.
+	IgnoreGuards bool
+}
+
+// Compute computes the metrics of all modules defined in files. References
+// are resolved using db.
+func Compute(db *ttcn3.DB, opts Options, files ...string) []*Module {
+	var (
+		mods    []*Module
+		defs    = make(map[*syntax.ModuleDef]*Definition)
+		refs    = make(map[*syntax.ModuleDef]map[*syntax.ModuleDef]bool)
+		modRefs = make(map[string]map[string]bool)
+	)
+
+	for _, file := range files {
+		tree := ttcn3.ParseFile(file)
+		if tree.Root == nil {
+			continue
+		}
+		for _, n := range tree.Modules() {
+			mod := n.Node.(*syntax.Module)
+			m := &Module{Name: syntax.Name(mod.Name), File: file}
+			m.LOC = loc(mod)
+			for _, md := range moduleDefs(mod) {
+				d := &Definition{
+					Name: m.Name + "." + name(md.Def),
+					Kind: kind(md.Def),
+					File: file,
+					Line: syntax.Begin(md).Line,
+				}
+				d.LOC = loc(md)
+				d.Complexity = Complexity(md.Def, opts.IgnoreGuards)
+				d.Nesting = nesting(md.Def)
+				d.AltBranches = altBranches(md.Def)
+				if d.Kind == "template" {
+					m.Templates++
+				}
+
+				refs[md] = make(map[*syntax.ModuleDef]bool)
+				for _, ref := range ttcn3.References(md.Def) {
+					for _, def := range tree.LookupWithDB(ref, db) {
+						if def.Tree == nil {
+							continue
+						}
+						if x := def.Tree.ModuleDefOf(def.Node); x != nil && x != md {
+							refs[md][x] = true
+						}
+					}
+				}
+
+				m.Complexity += d.Complexity
+				m.AltBranches += d.AltBranches
+				if d.Nesting > m.Nesting {
+					m.Nesting = d.Nesting
+				}
+				defs[md] = d
+				m.Definitions = append(m.Definitions, d)
+			}
+			mods = append(mods, m)
+		}
+	}
+
+	// Fan-in and fan-out
+	for from, tos := range refs {
+		d := defs[from]
+		for to := range tos {
+			target, ok := defs[to]
+			if !ok {
+				continue
+			}
+			d.FanOut++
+			target.FanIn++
+			if target.Kind == "template" {
+				d.Templates++
+			}
+			src, dst := ttcn3.ModuleName(d.Name), ttcn3.ModuleName(target.Name)
+			if src != dst {
+				if modRefs[src] == nil {
+					modRefs[src] = make(map[string]bool)
+				}
+				modRefs[src][dst] = true
+			}
+		}
+	}
+	byName := make(map[string]*Module)
+	for _, m := range mods {
+		byName[m.Name] = m
+	}
+	for src, dsts := range modRefs {
+		byName[src].FanOut += len(dsts)
+		for dst := range dsts {
+			byName[dst].FanIn++
+		}
+	}
+
+	sort.SliceStable(mods, func(i, j int) bool { return mods[i].Name < mods[j].Name })
+	return mods
+}
+
+// moduleDefs returns all module definitions of a module, including those in
+// groups, but without imports and friend declarations.
+func moduleDefs(mod *syntax.Module) []*syntax.ModuleDef {
+	var defs []*syntax.ModuleDef
+	mod.Inspect(func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.Module, *syntax.GroupDecl:
+			return true
+		case *syntax.ModuleDef:
+			switch n.Def.(type) {
+			case *syntax.GroupDecl:
+				return true
+			case *syntax.ImportDecl, *syntax.FriendDecl:
+			default:
+				defs = append(defs, n)
+			}
+		}
+		return false
+	})
+	return defs
+}
+
+func name(n syntax.Node) string {
+	switch n := n.(type) {
+	case *syntax.ValueDecl:
+		var names []string
+		for _, d := range n.Decls {
+			names = append(names, syntax.Name(d))
+		}
+		return strings.Join(names, ",")
+	case *syntax.ModuleParameterGroup:
+		var names []string
+		for _, v := range n.Decls {
+			names = append(names, name(v))
+		}
+		return strings.Join(names, ",")
+	case *syntax.ControlPart:
+		return "control"
+	}
+	return syntax.Name(n)
+}
+
+func kind(n syntax.Node) string {
+	switch n.(type) {
+	case *syntax.ControlPart:
+		return "control"
+	case *syntax.ModuleParameterGroup:
+		return "modulepar"
+	}
+	if k := imports.Kind(n); k != "" {
+		return k
+	}
+	return "other"
+}
+
+// loc returns the number of lines containing tokens other than comments.
+func loc(n syntax.Node) int {
+	var (
+		lines = make(map[int]bool)
+		last  = n.LastTok()
+	)
+	for tok := n.FirstTok(); tok != nil; tok = tok.NextTok() {
+		if tok.Kind() != syntax.COMMENT {
+			b, e := syntax.Begin(tok), syntax.End(tok)
+			for l := b.Line; l <= e.Line; l++ {
+				lines[l] = true
+			}
+		}
+		if tok.Pos() == last.Pos() {
+			break
+		}
+	}
+	return len(lines)
+}
+
+// Complexity returns the cyclomatic complexity (McCabe) of a behaviour. Other
+// definitions have no complexity. Default values of parameters are not
+// counted.
+func Complexity(n syntax.Node, ignoreGuards bool) int {
+	if _, ok := n.(*syntax.FuncDecl); !ok {
+		return 0
+	}
+	cc := 1
+	n.Inspect(func(n syntax.Node) bool {
+		if _, ok := n.(*syntax.FormalPar); ok {
+			return false
+		}
+		cc += Decisions(n, ignoreGuards)
+		return true
+	})
+	return cc
+}
+
+// Decisions returns by how much node n itself increases the cyclomatic
+// complexity: boolean operators, if-statements, case clauses other than case
+// else, and guards of alternatives count. Guards with expressions count twice.
+// Else-guards do not count.
+func Decisions(n syntax.Node, ignoreGuards bool) int {
+	switch n := n.(type) {
+	case *syntax.BinaryExpr:
+		if n.Op.Kind() == syntax.AND || n.Op.Kind() == syntax.OR {
+			return 1
+		}
+	case *syntax.IfStmt:
+		return 1
+	case *syntax.CaseClause:
+		if n.Case != nil {
+			return 1
+		}
+	case *syntax.CommClause:
+		if ignoreGuards || n.Else != nil {
+			return 0
+		}
+		if n.X != nil {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+// nesting returns the maximum nesting depth of statement blocks. The body of
+// a behaviour and blocks holding alternatives do not count.
+func nesting(n syntax.Node) int {
+	var (
+		max   int
+		stack []syntax.Node
+		depth []int
+	)
+	n.Inspect(func(n syntax.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			depth = depth[:len(depth)-1]
+			return false
+		}
+		d := -1
+		if len(depth) > 0 {
+			d = depth[len(depth)-1]
+		}
+		if b, ok := n.(*syntax.BlockStmt); ok {
+			if len(stack) == 0 || !isAlt(stack[len(stack)-1], b) {
+				d++
+			}
+		}
+		if d > max {
+			max = d
+		}
+		stack = append(stack, n)
+		depth = append(depth, d)
+		return true
+	})
+	return max
+}
+
+func isAlt(parent syntax.Node, b *syntax.BlockStmt) bool {
+	x, ok := parent.(*syntax.AltStmt)
+	return ok && x.Body == b
+}
+
+// altBranches returns the number of alternatives in alt statements, interleave
+// statements and altsteps.
+func altBranches(n syntax.Node) int {
+	var count int
+	inAlt := false
+	if f, ok := n.(*syntax.FuncDecl); ok && f.KindTok != nil && f.KindTok.Kind() == syntax.ALTSTEP {
+		inAlt = true
+	}
+	var visit func(n syntax.Node, inAlt bool)
+	visit = func(n syntax.Node, inAlt bool) {
+		n.Inspect(func(c syntax.Node) bool {
+			switch c := c.(type) {
+			case *syntax.AltStmt:
+				if c != n {
+					visit(c, true)
+					return false
+				}
+			case *syntax.CommClause:
+				if inAlt {
+					count++
+				}
+				if c.Body != nil {
+					visit(c.Body, false)
+				}
+				return false
+			}
+			return true
+		})
+	}
+	visit(n, inAlt)
+	return count
+}
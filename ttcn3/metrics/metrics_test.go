@@ -0,0 +1,94 @@
+package metrics_test
+
+import (
+	"testing"
+
+	"github.com/nokia/ntt/internal/fs"
+	"github.com/nokia/ntt/ttcn3"
+	"github.com/nokia/ntt/ttcn3/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompute(t *testing.T) {
+	fs.SetContent("test://A.ttcn3", []byte(`module A {
+	import from B all;
+
+	// A comment
+	function f(integer x) return integer {
+		if (x > 1 and x < 10) {
+			while (true) {
+				if (x == 2) { return 1 }
+			}
+		}
+		return 0;
+	}
+
+	altstep as() runs on C {
+		[] p.receive(t) { f(1) }
+		[x > 1] p.receive { alt { [] p.receive {} [else] {} } }
+	}
+
+	testcase tc() runs on C {
+		select (f(2)) {
+			case (1) { setverdict(pass) }
+			case else {}
+		}
+	}
+}`))
+	fs.SetContent("test://B.ttcn3", []byte(`module B {
+	type port P message { inout integer }
+	type component C { port P p; var integer x }
+	template integer t := 1;
+	template integer u := 2;
+}`))
+
+	db := &ttcn3.DB{}
+	db.Index("test://A.ttcn3", "test://B.ttcn3")
+	mods := metrics.Compute(db, metrics.Options{}, "test://A.ttcn3", "test://B.ttcn3")
+	assert.Equal(t, 2, len(mods))
+
+	defs := make(map[string]*metrics.Definition)
+	for _, m := range mods {
+		for _, d := range m.Definitions {
+			defs[d.Name] = d
+		}
+	}
+
+	f := defs["A.f"]
+	assert.Equal(t, "function", f.Kind)
+	assert.Equal(t, 5, f.Line)
+	assert.Equal(t, 8, f.LOC)
+	assert.Equal(t, 4, f.Complexity)
+	assert.Equal(t, 3, f.Nesting)
+	assert.Equal(t, 2, f.FanIn)
+	assert.Equal(t, 0, f.FanOut)
+
+	as := defs["A.as"]
+	assert.Equal(t, "altstep", as.Kind)
+	assert.Equal(t, 4, as.AltBranches)
+	assert.Equal(t, 1, as.Templates)
+	assert.Equal(t, 3, as.FanOut) // f, C and t
+	assert.Equal(t, 1, defs["B.P"].FanIn)
+
+	tc := defs["A.tc"]
+	assert.Equal(t, 2, tc.Complexity)
+	assert.Equal(t, 0, tc.AltBranches)
+
+	a, b := mods[0], mods[1]
+	assert.Equal(t, "A", a.Name)
+	assert.Equal(t, 1, a.FanOut)
+	assert.Equal(t, 0, a.FanIn)
+	assert.Equal(t, 1, b.FanIn)
+	assert.Equal(t, 2, b.Templates)
+	assert.Equal(t, f.Complexity+as.Complexity+tc.Complexity, a.Complexity)
+}
+
+func TestComplexityGuards(t *testing.T) {
+	tree := ttcn3.Parse(`altstep as() {
+		[] p.receive {}
+		[x > 1] p.receive { alt { [] p.receive {} [else] {} } }
+	}`)
+	as := tree.Funcs()[0].Node
+	assert.Equal(t, 5, metrics.Complexity(as, false))
+	assert.Equal(t, 1, metrics.Complexity(as, true))
+}
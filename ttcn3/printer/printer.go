@@ -317,7 +317,9 @@ func (p *printer) print(values ...interface{}) {
 				return
 			}
 			p.print(n.Tok)
+			p.print(n.LParen)
 			p.print(n.Cond)
+			p.print(n.RParen)
 			p.print(n.Body)
 
 		case *syntax.DoWhileStmt:
@@ -327,14 +329,18 @@ func (p *printer) print(values ...interface{}) {
 			p.print(n.DoTok)
 			p.print(n.Body)
 			p.print(n.WhileTok)
+			p.print(n.LParen)
 			p.print(n.Cond)
+			p.print(n.RParen)
 
 		case *syntax.IfStmt:
 			if n == nil {
 				return
 			}
 			p.print(n.Tok)
+			p.print(n.LParen)
 			p.print(n.Cond)
+			p.print(n.RParen)
 			p.print(n.Then)
 			p.print(n.ElseTok)
 			p.print(n.Else)
@@ -459,7 +465,7 @@ func (p *printer) print(values ...interface{}) {
 			if n == nil {
 				return
 			}
-			p.print(&n.RestrictionSpec)
+			p.print(n.RestrictionSpec)
 			p.print(n.Modif)
 			p.print(n.Type)
 			p.print(n.Name)
@@ -629,8 +635,8 @@ func (p *printer) print(values ...interface{}) {
 			if n == nil {
 				return
 			}
-			p.print(n.Def)
 			p.print(n.Visibility)
+			p.print(n.Def)
 
 		case *syntax.ControlPart:
 			if n == nil {
@@ -807,8 +813,6 @@ func (p *printer) print(values ...interface{}) {
 				p.print(item)
 				if i < len(n)-1 {
 					p.print(",")
-				} else {
-					p.print(";", "\n")
 				}
 			}
 		case []*syntax.WithStmt:
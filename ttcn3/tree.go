@@ -107,6 +107,20 @@ func (t *Tree) ModuleOf(n syntax.Node) *syntax.Module {
 	return nil
 }
 
+// ModuleDefOf returns the module definition enclosing the given node, by
+// walking up the tree.
+func (t *Tree) ModuleDefOf(n syntax.Node) *syntax.ModuleDef {
+	if syntax.IsNil(n) {
+		return nil
+	}
+	for n := n; n != nil; n = t.ParentOf(n) {
+		if md, ok := n.(*syntax.ModuleDef); ok {
+			return md
+		}
+	}
+	return nil
+}
+
 func (t *Tree) Modules() []*Node {
 	var defs []*Node
 	t.Inspect(func(n syntax.Node) bool {
@@ -288,6 +302,30 @@ func (t *Tree) LookupWithDB(n syntax.Expr, db *DB) []*Node {
 
 }
 
+// References returns all references in n, which need to be resolved by
+// Lookup. Qualified identifiers and field references are returned as a whole,
+// but their base expression might also contain references.
+func References(n syntax.Node) []syntax.Expr {
+	var refs []syntax.Expr
+	n.Inspect(func(n syntax.Node) bool {
+		switch n := n.(type) {
+		case *syntax.SelectorExpr:
+			refs = append(refs, n)
+			if n.X != nil {
+				refs = append(refs, References(n.X)...)
+			}
+			return false
+		case *syntax.Ident:
+			if !n.IsName && n.Tok2 == nil {
+				refs = append(refs, n)
+			}
+			return false
+		}
+		return true
+	})
+	return refs
+}
+
 func (t *Tree) TypeOf(n syntax.Node, db *DB) []*Node {
 	return newFinder(db).typeOf(&Node{Node: n, Tree: t})
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nokia/ntt/internal/log"
+	"github.com/nokia/ntt/project"
+	"github.com/spf13/cobra"
+)
+
+// workspaceMode makes commands operate on all known test suites instead of
+// the current project only.
+var workspaceMode bool
+
+func init() {
+	addWorkspaceFlag(FormatCommand, LintCommand, ListCommand, TagsCommand)
+}
+
+// addWorkspaceFlag adds the --workspace flag to the given commands.
+func addWorkspaceFlag(cmds ...*cobra.Command) {
+	for _, cmd := range cmds {
+		cmd.PersistentFlags().BoolVarP(&workspaceMode, "workspace", "W", false, "operate on all test suites of the workspace and the index file (ttcn3_suites.json)")
+	}
+}
+
+// suiteGroups returns the files of the current project. In workspace mode
+// suiteGroups returns the files of every test suite found by discoverSuites,
+// grouped by suite. Files shared by several suites are listed only once.
+func suiteGroups(files func(*project.Config) ([]string, error)) ([]project.Group, error) {
+	if !workspaceMode {
+		list, err := files(Project)
+		return []project.Group{{Suite: Project, Files: list}}, err
+	}
+
+	w, err := project.NewWorkspace(discoverSuites(".")...)
+	if err != nil {
+		// Continue with the suites we could open.
+		log.Verboseln(err.Error())
+	}
+	if len(w.Suites) == 0 {
+		return nil, fmt.Errorf("no test suites found")
+	}
+	return w.Groups(files)
+}
+
+// printSuiteHeader separates the output of test suites in workspace mode.
+func printSuiteHeader(w io.Writer, g project.Group) {
+	if !workspaceMode || outputJSON {
+		return
+	}
+	fmt.Fprintf(w, "=== %s (%s)\n", g.Suite.Name, g.Suite.Root)
+}